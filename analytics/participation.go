@@ -0,0 +1,253 @@
+// Package analytics derives validator statistics from verified headers.
+package analytics
+
+import (
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/nspcc-dev/neo-go/pkg/core/block"
+	"github.com/txhsl/dbft-verifier/store"
+	"github.com/txhsl/dbft-verifier/syncer"
+	n3 "github.com/txhsl/n3-dbft-verifier"
+	neox "github.com/txhsl/neox-dbft-verifier"
+)
+
+// Block is the participation of validators in a block, Signers are indexes of
+// Validators.
+type Block struct {
+	Height     uint64
+	Validators []string
+	Signers    []int
+}
+
+// Streak is a run of blocks missed by a validator, from and to inclusive. Blocks the
+// validator isn't expected to sign don't break it and aren't counted in Missed.
+type Streak struct {
+	From   uint64 `json:"from"`
+	To     uint64 `json:"to"`
+	Missed int    `json:"missed"`
+}
+
+// ValidatorStats is the participation of a validator in the blocks it was expected to
+// sign, i.e. the blocks it was a validator of.
+type ValidatorStats struct {
+	Validator     string   `json:"validator"`
+	Expected      int      `json:"expected"`
+	Signed        int      `json:"signed"`
+	Missed        int      `json:"missed"`
+	Rate          float64  `json:"rate"`
+	LongestStreak int      `json:"longestMissStreak"`
+	MissStreaks   []Streak `json:"missStreaks"`
+
+	// last is the height of the last expected block.
+	last uint64
+}
+
+// ParticipationReport is the participation of validators over a height range. Skipped
+// blocks don't reveal their signers, e.g. Neo X threshold scheme blocks.
+type ParticipationReport struct {
+	From       uint64            `json:"from"`
+	To         uint64            `json:"to"`
+	Blocks     int               `json:"blocks"`
+	Skipped    int               `json:"skipped"`
+	Validators []*ValidatorStats `json:"validators"`
+}
+
+// Participation accumulates blocks in ascending height order into a report.
+type Participation struct {
+	report ParticipationReport
+	stats  map[string]*ValidatorStats
+	empty  bool
+}
+
+func NewParticipation() *Participation {
+	return &Participation{stats: make(map[string]*ValidatorStats), empty: true}
+}
+
+func (p *Participation) Add(b Block) {
+	p.extend(b.Height)
+	p.report.Blocks++
+	signed := make([]bool, len(b.Validators))
+	for _, i := range b.Signers {
+		signed[i] = true
+	}
+	for i, validator := range b.Validators {
+		s, ok := p.stats[validator]
+		if !ok {
+			s = &ValidatorStats{Validator: validator}
+			p.stats[validator] = s
+		}
+		s.Expected++
+		if signed[i] {
+			s.Signed++
+		} else {
+			s.Missed++
+			if n := len(s.MissStreaks); n > 0 && s.MissStreaks[n-1].To == s.last {
+				s.MissStreaks[n-1].To = b.Height
+				s.MissStreaks[n-1].Missed++
+			} else {
+				s.MissStreaks = append(s.MissStreaks, Streak{From: b.Height, To: b.Height, Missed: 1})
+			}
+		}
+		s.last = b.Height
+	}
+}
+
+// Skip counts a block with unknown signers.
+func (p *Participation) Skip(height uint64) {
+	p.extend(height)
+	p.report.Skipped++
+}
+
+func (p *Participation) extend(height uint64) {
+	if p.empty {
+		p.report.From, p.empty = height, false
+	}
+	p.report.To = height
+}
+
+// Report returns the report of the blocks added so far, validators are sorted.
+func (p *Participation) Report() *ParticipationReport {
+	r := p.report
+	r.Validators = make([]*ValidatorStats, 0, len(p.stats))
+	for _, s := range p.stats {
+		c := *s
+		c.MissStreaks = slices.Clone(s.MissStreaks)
+		if c.MissStreaks == nil {
+			c.MissStreaks = []Streak{}
+		}
+		c.Rate = float64(c.Signed) / float64(c.Expected)
+		for _, streak := range c.MissStreaks {
+			c.LongestStreak = max(c.LongestStreak, streak.Missed)
+		}
+		r.Validators = append(r.Validators, &c)
+	}
+	slices.SortFunc(r.Validators, func(a, b *ValidatorStats) int {
+		return strings.Compare(a.Validator, b.Validator)
+	})
+	return &r
+}
+
+// WriteCSV writes a row per validator with a header, streaks are space separated
+// from-to ranges.
+func (r *ParticipationReport) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"validator", "expected", "signed", "missed", "rate", "longest_miss_streak", "miss_streaks"})
+	for _, s := range r.Validators {
+		streaks := make([]string, len(s.MissStreaks))
+		for i, streak := range s.MissStreaks {
+			streaks[i] = fmt.Sprintf("%d-%d", streak.From, streak.To)
+		}
+		cw.Write([]string{
+			s.Validator,
+			strconv.Itoa(s.Expected),
+			strconv.Itoa(s.Signed),
+			strconv.Itoa(s.Missed),
+			strconv.FormatFloat(s.Rate, 'f', 4, 64),
+			strconv.Itoa(s.LongestStreak),
+			strings.Join(streaks, " "),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// N3Block returns the participation in a verified N3 header, validators are hex
+// encoded public keys.
+func N3Block(header *block.Header, network uint32) (Block, error) {
+	validators, signers, err := n3.Signers(header, network)
+	if err != nil {
+		return Block{}, err
+	}
+	b := Block{Height: uint64(header.Index), Signers: signers}
+	for _, key := range validators {
+		b.Validators = append(b.Validators, hex.EncodeToString(key.Bytes()))
+	}
+	return b, nil
+}
+
+// NeoXBlock returns the participation in a verified Neo X header, validators are hex
+// addresses. It fails with neox.ErrHiddenSigners for threshold scheme headers.
+func NeoXBlock(config *neox.ChainConfig, header *types.Header) (Block, error) {
+	validators, signers, err := neox.Signers(config, header)
+	if err != nil {
+		return Block{}, err
+	}
+	b := Block{Height: header.Number.Uint64(), Signers: signers}
+	for _, addr := range validators {
+		b.Validators = append(b.Validators, addr.Hex())
+	}
+	return b, nil
+}
+
+// N3Participation returns the participation report of stored N3 headers from and to
+// inclusive.
+func N3Participation(st *store.Store, network uint32, from, to uint64) (*ParticipationReport, error) {
+	p := NewParticipation()
+	err := scan(st, from, to, func(data []byte) error {
+		header, err := syncer.DecodeN3Header(data)
+		if err != nil {
+			return err
+		}
+		b, err := N3Block(header, network)
+		if err != nil {
+			return fmt.Errorf("header %d: %w", header.Index, err)
+		}
+		p.Add(b)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return p.Report(), nil
+}
+
+// NeoXParticipation returns the participation report of stored Neo X headers from and
+// to inclusive, threshold scheme headers are skipped.
+func NeoXParticipation(st *store.Store, config *neox.ChainConfig, from, to uint64) (*ParticipationReport, error) {
+	p := NewParticipation()
+	err := scan(st, from, to, func(data []byte) error {
+		header, err := syncer.DecodeNeoXHeader(data)
+		if err != nil {
+			return err
+		}
+		b, err := NeoXBlock(config, header)
+		if errors.Is(err, neox.ErrHiddenSigners) {
+			p.Skip(header.Number.Uint64())
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("header %d: %w", header.Number, err)
+		}
+		p.Add(b)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return p.Report(), nil
+}
+
+// scan calls f with stored headers from and to inclusive.
+func scan(st *store.Store, from, to uint64, f func(data []byte) error) error {
+	if from > to {
+		return errors.New("empty range")
+	}
+	for height := from; height <= to; height++ {
+		data, err := st.Get(height)
+		if err != nil {
+			return fmt.Errorf("header %d: %w", height, err)
+		}
+		if err := f(data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package analytics
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/nspcc-dev/neo-go/pkg/core/block"
+	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/stretchr/testify/require"
+	"github.com/txhsl/dbft-verifier/store"
+	neox "github.com/txhsl/neox-dbft-verifier"
+)
+
+func TestParticipation(t *testing.T) {
+	p := NewParticipation()
+	validators := []string{"a", "b", "c"}
+	p.Add(Block{Height: 10, Validators: validators, Signers: []int{0, 1}})
+	p.Add(Block{Height: 11, Validators: validators, Signers: []int{0}})
+	p.Skip(12)
+	p.Add(Block{Height: 13, Validators: validators, Signers: []int{0}})
+	p.Add(Block{Height: 14, Validators: []string{"a", "d"}, Signers: []int{0, 1}})
+	p.Add(Block{Height: 15, Validators: validators, Signers: []int{1}})
+	r := p.Report()
+	require.Equal(t, uint64(10), r.From)
+	require.Equal(t, uint64(15), r.To)
+	require.Equal(t, 5, r.Blocks)
+	require.Equal(t, 1, r.Skipped)
+	require.Equal(t, []*ValidatorStats{
+		{Validator: "a", Expected: 5, Signed: 4, Missed: 1, Rate: 0.8, LongestStreak: 1, MissStreaks: []Streak{{15, 15, 1}}},
+		{Validator: "b", Expected: 4, Signed: 2, Missed: 2, Rate: 0.5, LongestStreak: 2, MissStreaks: []Streak{{11, 13, 2}}},
+		{Validator: "c", Expected: 4, Signed: 0, Missed: 4, Rate: 0, LongestStreak: 4, MissStreaks: []Streak{{10, 15, 4}}},
+		{Validator: "d", Expected: 1, Signed: 1, Missed: 0, Rate: 1, MissStreaks: []Streak{}},
+	}, clearLast(r.Validators))
+
+	var buf bytes.Buffer
+	require.NoError(t, r.WriteCSV(&buf))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Equal(t, 5, len(lines))
+	require.Equal(t, "validator,expected,signed,missed,rate,longest_miss_streak,miss_streaks", lines[0])
+	require.Equal(t, "c,4,0,4,0.0000,4,10-15", lines[3])
+}
+
+// clearLast resets unexported fields for comparison.
+func clearLast(stats []*ValidatorStats) []*ValidatorStats {
+	for _, s := range stats {
+		s.last = 0
+	}
+	return stats
+}
+
+// newN3Store returns a store of the N3 test headers 9999 and 10000.
+func newN3Store(t *testing.T) *store.Store {
+	data, err := os.ReadFile("../bundle/testdata/n3_headers.json")
+	require.NoError(t, err)
+	var raws []json.RawMessage
+	require.NoError(t, json.Unmarshal(data, &raws))
+	st := store.NewMemory()
+	for _, raw := range raws {
+		header := new(block.Header)
+		require.NoError(t, header.UnmarshalJSON(raw))
+		w := io.NewBufBinWriter()
+		header.EncodeBinary(w.BinWriter)
+		require.NoError(t, w.Err)
+		require.NoError(t, st.Put(uint64(header.Index), []store.Header{{Hash: header.Hash().BytesBE(), Data: w.Bytes()}}))
+	}
+	return st
+}
+
+// newNeoXStore returns a store of the Neo X test headers and their height range.
+func newNeoXStore(t *testing.T) (*store.Store, uint64, uint64) {
+	data, err := os.ReadFile("../bundle/testdata/neox_headers.json")
+	require.NoError(t, err)
+	var headers []*types.Header
+	require.NoError(t, json.Unmarshal(data, &headers))
+	st := store.NewMemory()
+	for _, header := range headers {
+		data, err := rlp.EncodeToBytes(header)
+		require.NoError(t, err)
+		require.NoError(t, st.Put(header.Number.Uint64(), []store.Header{{Hash: header.Hash().Bytes(), Data: data}}))
+	}
+	return st, headers[0].Number.Uint64(), headers[len(headers)-1].Number.Uint64()
+}
+
+func TestN3Participation(t *testing.T) {
+	st := newN3Store(t)
+	r, err := N3Participation(st, 860833102, 9999, 10000)
+	require.NoError(t, err)
+	require.Equal(t, 2, r.Blocks)
+	require.Equal(t, 7, len(r.Validators))
+	var signed int
+	for _, s := range r.Validators {
+		require.Equal(t, 2, s.Expected)
+		signed += s.Signed
+	}
+	require.Equal(t, 10, signed)
+
+	_, err = N3Participation(st, 860833102, 9999, 10001)
+	require.ErrorIs(t, err, store.ErrNotFound)
+	_, err = N3Participation(st, 894710606, 9999, 10000)
+	require.Error(t, err)
+	_, err = N3Participation(st, 860833102, 10000, 9999)
+	require.Error(t, err)
+}
+
+func TestNeoXParticipation(t *testing.T) {
+	st, from, to := newNeoXStore(t)
+	r, err := NeoXParticipation(st, neox.DefaultChainConfig, from, to)
+	require.NoError(t, err)
+	require.Equal(t, int(to-from+1), r.Blocks+r.Skipped)
+	require.Equal(t, from, r.From)
+	require.Equal(t, to, r.To)
+}
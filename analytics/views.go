@@ -0,0 +1,256 @@
+package analytics
+
+import (
+	"encoding/csv"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/nspcc-dev/neo-go/pkg/core/block"
+	"github.com/nspcc-dev/neo-go/pkg/vm"
+	"github.com/txhsl/dbft-verifier/store"
+	"github.com/txhsl/dbft-verifier/syncer"
+	n3 "github.com/txhsl/n3-dbft-verifier"
+	neox "github.com/txhsl/neox-dbft-verifier"
+)
+
+// DefaultFailureThreshold is the primary failure rate above which validators are
+// flagged.
+const DefaultFailureThreshold = 0.1
+
+// Round is the round a block was accepted in, Primaries are the indexes of the
+// primaries of views 0 to View, the last one proposed the block. Only the proposer is
+// known if the view is only known to be a non-zero multiple of the validators count,
+// it's View then and no failures are attributed. Validators are nil if the header
+// doesn't reveal them, primaries are then identified by index.
+type Round struct {
+	Height     uint64
+	View       int
+	Primaries  []int
+	Validators []string
+}
+
+// ViewChange is a block accepted after a view change, Primary is the validator that
+// proposed it.
+type ViewChange struct {
+	Height  uint64 `json:"height"`
+	View    int    `json:"view"`
+	Primary string `json:"primary"`
+}
+
+// PrimaryStats are the rounds a validator was the primary of. Failed are the views
+// that were changed, Flagged is set for a FailureRate above the report threshold.
+type PrimaryStats struct {
+	Validator   string  `json:"validator"`
+	Proposed    int     `json:"proposed"`
+	Failed      int     `json:"failed"`
+	FailureRate float64 `json:"failureRate"`
+	Flagged     bool    `json:"flagged"`
+}
+
+// ViewReport is the view change frequency over a height range, Views counts blocks
+// by the view they were accepted in.
+type ViewReport struct {
+	From           uint64          `json:"from"`
+	To             uint64          `json:"to"`
+	Blocks         int             `json:"blocks"`
+	ViewChanges    int             `json:"viewChanges"`
+	ViewChangeRate float64         `json:"viewChangeRate"`
+	Threshold      float64         `json:"threshold"`
+	Views          map[int]int     `json:"views"`
+	Changes        []ViewChange    `json:"changes"`
+	Primaries      []*PrimaryStats `json:"primaries"`
+}
+
+// Views accumulates rounds in ascending height order into a report.
+type Views struct {
+	report ViewReport
+	stats  map[string]*PrimaryStats
+}
+
+// NewViews returns an accumulator flagging primaries failing more often than
+// threshold.
+func NewViews(threshold float64) *Views {
+	return &Views{
+		report: ViewReport{Threshold: threshold, Views: make(map[int]int), Changes: []ViewChange{}},
+		stats:  make(map[string]*PrimaryStats),
+	}
+}
+
+func (v *Views) Add(r Round) {
+	if v.report.Blocks == 0 {
+		v.report.From = r.Height
+	}
+	v.report.To = r.Height
+	v.report.Blocks++
+	v.report.Views[r.View]++
+	last := len(r.Primaries) - 1
+	for view, i := range r.Primaries {
+		s := v.primary(r.Validators, i)
+		if view < last {
+			s.Failed++
+		} else {
+			s.Proposed++
+		}
+	}
+	if r.View > 0 {
+		v.report.ViewChanges++
+		v.report.Changes = append(v.report.Changes, ViewChange{
+			Height:  r.Height,
+			View:    r.View,
+			Primary: label(r.Validators, r.Primaries[last]),
+		})
+	}
+}
+
+func (v *Views) primary(validators []string, i int) *PrimaryStats {
+	validator := label(validators, i)
+	s, ok := v.stats[validator]
+	if !ok {
+		s = &PrimaryStats{Validator: validator}
+		v.stats[validator] = s
+	}
+	return s
+}
+
+// label returns the validator at index i, or the index if validators are unknown.
+func label(validators []string, i int) string {
+	if i < len(validators) {
+		return validators[i]
+	}
+	return strconv.Itoa(i)
+}
+
+// Report returns the report of the rounds added so far, primaries are sorted.
+func (v *Views) Report() *ViewReport {
+	r := v.report
+	r.Views = make(map[int]int, len(v.report.Views))
+	for view, n := range v.report.Views {
+		r.Views[view] = n
+	}
+	r.Changes = slices.Clone(v.report.Changes)
+	if r.Blocks > 0 {
+		r.ViewChangeRate = float64(r.ViewChanges) / float64(r.Blocks)
+	}
+	r.Primaries = make([]*PrimaryStats, 0, len(v.stats))
+	for _, s := range v.stats {
+		c := *s
+		c.FailureRate = float64(c.Failed) / float64(c.Proposed+c.Failed)
+		c.Flagged = c.FailureRate > r.Threshold
+		r.Primaries = append(r.Primaries, &c)
+	}
+	slices.SortFunc(r.Primaries, func(a, b *PrimaryStats) int {
+		return strings.Compare(a.Validator, b.Validator)
+	})
+	return &r
+}
+
+// WriteCSV writes a row per primary with a header.
+func (r *ViewReport) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"validator", "proposed", "failed", "failure_rate", "flagged"})
+	for _, s := range r.Primaries {
+		cw.Write([]string{
+			s.Validator,
+			strconv.Itoa(s.Proposed),
+			strconv.Itoa(s.Failed),
+			strconv.FormatFloat(s.FailureRate, 'f', 4, 64),
+			strconv.FormatBool(s.Flagged),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// N3Round returns the round of an N3 header, validators are hex encoded public keys of
+// its verification script. Views are known modulo the validators count.
+func N3Round(header *block.Header) (Round, error) {
+	view, err := n3.View(header)
+	if err != nil {
+		return Round{}, err
+	}
+	// View checked the script
+	_, pubs, _ := vm.ParseMultiSigContract(header.Script.VerificationScript)
+	r := Round{Height: uint64(header.Index), View: view}
+	for v := 0; v <= view; v++ {
+		r.Primaries = append(r.Primaries, n3.Primary(header.Index, v, len(pubs)))
+	}
+	for _, pub := range pubs {
+		r.Validators = append(r.Validators, hex.EncodeToString(pub))
+	}
+	return r, nil
+}
+
+// NeoXRound returns the round of a Neo X header, validators are hex addresses of its
+// extra. Threshold scheme headers don't reveal validators, they're counted as n. A
+// view change back to the primary of view 0 is a round of the validators count view
+// with only its proposer.
+func NeoXRound(header *types.Header, n int) (Round, error) {
+	n = neox.HeaderValidatorsCount(header, n)
+	view, err := neox.View(header, n)
+	if err != nil {
+		return Round{}, err
+	}
+	r := Round{Height: header.Number.Uint64(), View: view}
+	if view == n {
+		// The primaries of the views before are unknown
+		r.Primaries = []int{neox.Primary(header.Number.Uint64(), view, n)}
+	} else {
+		for v := 0; v <= view; v++ {
+			r.Primaries = append(r.Primaries, neox.Primary(header.Number.Uint64(), v, n))
+		}
+	}
+	if e, err := neox.ParseExtra(header.Extra); err == nil {
+		for _, addr := range e.Addresses {
+			r.Validators = append(r.Validators, addr.Hex())
+		}
+	}
+	return r, nil
+}
+
+// N3Views returns the view report of stored N3 headers from and to inclusive.
+func N3Views(st *store.Store, from, to uint64, threshold float64) (*ViewReport, error) {
+	v := NewViews(threshold)
+	err := scan(st, from, to, func(data []byte) error {
+		header, err := syncer.DecodeN3Header(data)
+		if err != nil {
+			return err
+		}
+		r, err := N3Round(header)
+		if err != nil {
+			return fmt.Errorf("header %d: %w", header.Index, err)
+		}
+		v.Add(r)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.Report(), nil
+}
+
+// NeoXViews returns the view report of stored Neo X headers from and to inclusive, n
+// is the validators count of headers that don't reveal the validator set.
+func NeoXViews(st *store.Store, from, to uint64, n int, threshold float64) (*ViewReport, error) {
+	v := NewViews(threshold)
+	err := scan(st, from, to, func(data []byte) error {
+		header, err := syncer.DecodeNeoXHeader(data)
+		if err != nil {
+			return err
+		}
+		r, err := NeoXRound(header, n)
+		if err != nil {
+			return fmt.Errorf("header %d: %w", header.Number, err)
+		}
+		v.Add(r)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.Report(), nil
+}
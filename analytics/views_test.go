@@ -0,0 +1,103 @@
+package analytics
+
+import (
+	"bytes"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+	"github.com/txhsl/dbft-verifier/store"
+	neox "github.com/txhsl/neox-dbft-verifier"
+)
+
+func TestViews(t *testing.T) {
+	v := NewViews(0.3)
+	validators := []string{"a", "b", "c"}
+	v.Add(Round{Height: 10, View: 0, Primaries: []int{1}, Validators: validators})
+	v.Add(Round{Height: 11, View: 1, Primaries: []int{2, 1}, Validators: validators})
+	v.Add(Round{Height: 12, View: 0, Primaries: []int{0}, Validators: validators})
+	v.Add(Round{Height: 13, View: 2, Primaries: []int{1, 0, 2}})
+	r := v.Report()
+	require.Equal(t, uint64(10), r.From)
+	require.Equal(t, uint64(13), r.To)
+	require.Equal(t, 4, r.Blocks)
+	require.Equal(t, 2, r.ViewChanges)
+	require.Equal(t, 0.5, r.ViewChangeRate)
+	require.Equal(t, map[int]int{0: 2, 1: 1, 2: 1}, r.Views)
+	require.Equal(t, []ViewChange{{Height: 11, View: 1, Primary: "b"}, {Height: 13, View: 2, Primary: "2"}}, r.Changes)
+	require.Equal(t, []*PrimaryStats{
+		{Validator: "0", Proposed: 0, Failed: 1, FailureRate: 1, Flagged: true},
+		{Validator: "1", Proposed: 0, Failed: 1, FailureRate: 1, Flagged: true},
+		{Validator: "2", Proposed: 1, Failed: 0, FailureRate: 0},
+		{Validator: "a", Proposed: 1, Failed: 0, FailureRate: 0},
+		{Validator: "b", Proposed: 2, Failed: 0, FailureRate: 0},
+		{Validator: "c", Proposed: 0, Failed: 1, FailureRate: 1, Flagged: true},
+	}, r.Primaries)
+
+	var buf bytes.Buffer
+	require.NoError(t, r.WriteCSV(&buf))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Equal(t, 7, len(lines))
+	require.Equal(t, "validator,proposed,failed,failure_rate,flagged", lines[0])
+	require.Equal(t, "c,0,1,1.0000,true", lines[6])
+
+	r = NewViews(DefaultFailureThreshold).Report()
+	require.Equal(t, 0, r.Blocks)
+	require.Equal(t, float64(0), r.ViewChangeRate)
+}
+
+func TestN3Views(t *testing.T) {
+	st := newN3Store(t)
+	r, err := N3Views(st, 9999, 10000, DefaultFailureThreshold)
+	require.NoError(t, err)
+	require.Equal(t, 2, r.Blocks)
+	require.Equal(t, 0, r.ViewChanges)
+	require.Equal(t, map[int]int{0: 2}, r.Views)
+	require.Equal(t, 2, len(r.Primaries))
+	for _, s := range r.Primaries {
+		require.Equal(t, 66, len(s.Validator))
+		require.Equal(t, 1, s.Proposed)
+		require.Equal(t, false, s.Flagged)
+	}
+
+	_, err = N3Views(st, 9999, 10001, DefaultFailureThreshold)
+	require.ErrorIs(t, err, store.ErrNotFound)
+}
+
+func TestNeoXViews(t *testing.T) {
+	st, from, to := newNeoXStore(t)
+	r, err := NeoXViews(st, from, to, neox.ValidatorsCount, DefaultFailureThreshold)
+	require.NoError(t, err)
+	require.Equal(t, int(to-from+1), r.Blocks)
+	require.Equal(t, 0, r.ViewChanges)
+	require.Equal(t, int(to-from+1), len(r.Primaries))
+	for _, s := range r.Primaries {
+		require.Equal(t, 0, s.Failed)
+	}
+}
+
+func TestNeoXRoundWrapped(t *testing.T) {
+	// View change back to the primary of view 0
+	header := &types.Header{
+		Number:     big.NewInt(15),
+		Difficulty: big.NewInt(neox.DiffNoTurn),
+		Nonce:      types.EncodeNonce(uint64(neox.Primary(15, 0, neox.ValidatorsCount))),
+	}
+	r, err := NeoXRound(header, neox.ValidatorsCount)
+	require.NoError(t, err)
+	require.Equal(t, Round{Height: 15, View: neox.ValidatorsCount, Primaries: []int{1}}, r)
+
+	v := NewViews(DefaultFailureThreshold)
+	v.Add(r)
+	report := v.Report()
+	require.Equal(t, 1, report.ViewChanges)
+	require.Equal(t, []ViewChange{{Height: 15, View: neox.ValidatorsCount, Primary: "1"}}, report.Changes)
+	require.Equal(t, []*PrimaryStats{{Validator: "1", Proposed: 1}}, report.Primaries)
+
+	// Four validators of a consensus list
+	r, err = NeoXRound(header, 4)
+	require.NoError(t, err)
+	require.Equal(t, Round{Height: 15, View: 2, Primaries: []int{3, 2, 1}}, r)
+}
@@ -0,0 +1,145 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/txhsl/dbft-verifier/analytics"
+	"github.com/txhsl/dbft-verifier/reason"
+	"github.com/txhsl/dbft-verifier/store"
+)
+
+// Analytics ranges are limited to maxAnalyticsRange headers and default to the last
+// defaultAnalyticsRange ones.
+const (
+	maxAnalyticsRange     = 100000
+	defaultAnalyticsRange = 1000
+)
+
+func (s *Server) n3Participation(w http.ResponseWriter, r *http.Request) {
+	from, to, ok := analyticsRange(w, r, s.n3.Store)
+	if !ok {
+		return
+	}
+	report, err := analytics.N3Participation(s.n3.Store, s.n3.Client.Network(), from, to)
+	writeReport(w, r, report, err)
+}
+
+func (s *Server) neoXParticipation(w http.ResponseWriter, r *http.Request) {
+	from, to, ok := analyticsRange(w, r, s.neoX.Store)
+	if !ok {
+		return
+	}
+	report, err := analytics.NeoXParticipation(s.neoX.Store, s.neoX.Client.Config(), from, to)
+	writeReport(w, r, report, err)
+}
+
+func (s *Server) n3Views(w http.ResponseWriter, r *http.Request) {
+	from, to, ok := analyticsRange(w, r, s.n3.Store)
+	if !ok {
+		return
+	}
+	threshold, ok := failureThreshold(w, r)
+	if !ok {
+		return
+	}
+	report, err := analytics.N3Views(s.n3.Store, from, to, threshold)
+	writeReport(w, r, report, err)
+}
+
+func (s *Server) neoXViews(w http.ResponseWriter, r *http.Request) {
+	from, to, ok := analyticsRange(w, r, s.neoX.Store)
+	if !ok {
+		return
+	}
+	threshold, ok := failureThreshold(w, r)
+	if !ok {
+		return
+	}
+	report, err := analytics.NeoXViews(s.neoX.Store, from, to, s.neoX.validatorsCount(), threshold)
+	writeReport(w, r, report, err)
+}
+
+// failureThreshold returns the threshold query parameter, a rate from 0 to 1 defaulting
+// to analytics.DefaultFailureThreshold. It writes the error response on failure.
+func failureThreshold(w http.ResponseWriter, r *http.Request) (float64, bool) {
+	v := r.URL.Query().Get("threshold")
+	if v == "" {
+		return analytics.DefaultFailureThreshold, true
+	}
+	threshold, err := strconv.ParseFloat(v, 64)
+	if err == nil && !(threshold >= 0 && threshold <= 1) {
+		err = errors.New("threshold must be from 0 to 1")
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, ReasonMalformed, err)
+		return 0, false
+	}
+	return threshold, true
+}
+
+// analyticsRange returns the from and to query parameters, to defaults to the head
+// and from to the start of the default range, but not below the lowest stored header.
+// It writes the error response on failure.
+func analyticsRange(w http.ResponseWriter, r *http.Request, st *store.Store) (uint64, uint64, bool) {
+	head, _, err := st.Head()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, ReasonInternal, err)
+		return 0, 0, false
+	}
+	tail, err := st.Tail()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, ReasonInternal, err)
+		return 0, 0, false
+	}
+	to := head
+	if v := r.URL.Query().Get("to"); v != "" {
+		if to, err = strconv.ParseUint(v, 10, 64); err != nil {
+			writeError(w, http.StatusBadRequest, ReasonMalformed, err)
+			return 0, 0, false
+		}
+	}
+	from := max(tail, to-min(to, defaultAnalyticsRange-1))
+	if v := r.URL.Query().Get("from"); v != "" {
+		if from, err = strconv.ParseUint(v, 10, 64); err != nil {
+			writeError(w, http.StatusBadRequest, ReasonMalformed, err)
+			return 0, 0, false
+		}
+	}
+	if from > to || to-from >= maxAnalyticsRange {
+		writeError(w, http.StatusBadRequest, ReasonMalformed, fmt.Errorf("range must have 1 to %d headers", maxAnalyticsRange))
+		return 0, 0, false
+	}
+	return from, to, true
+}
+
+// analyticsReport is a report that can be written as JSON or CSV.
+type analyticsReport interface {
+	WriteCSV(w io.Writer) error
+}
+
+// writeReport writes report as JSON or, with format=csv, as CSV. Stored headers that
+// fail the analysis are reported as unprocessable with the reason.
+func writeReport(w http.ResponseWriter, r *http.Request, report analyticsReport, err error) {
+	if errors.Is(err, store.ErrNotFound) {
+		writeError(w, http.StatusNotFound, ReasonNotFound, err)
+		return
+	}
+	if code := reason.Of(err); err != nil && code != ReasonInvalid {
+		writeError(w, http.StatusUnprocessableEntity, code, err)
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, ReasonInternal, err)
+		return
+	}
+	if r.URL.Query().Get("format") == "csv" {
+		w.Header().Set("Content-Type", "text/csv")
+		report.WriteCSV(w)
+		return
+	}
+	writeJSON(w, http.StatusOK, report)
+}
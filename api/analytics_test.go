@@ -0,0 +1,96 @@
+package api
+
+import (
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/require"
+	"github.com/txhsl/dbft-verifier/analytics"
+	"github.com/txhsl/dbft-verifier/store"
+	neox "github.com/txhsl/neox-dbft-verifier"
+)
+
+func TestParticipation(t *testing.T) {
+	server, _, _ := newTestServer(t)
+
+	for _, path := range []string{"/v1/n3/participation", "/v1/n3/participation?from=9999&to=9999"} {
+		var report analytics.ParticipationReport
+		require.Equal(t, http.StatusOK, get(t, server.URL+path, &report))
+		require.Equal(t, uint64(9999), report.From)
+		require.Equal(t, uint64(9999), report.To)
+		require.Equal(t, 1, report.Blocks)
+		require.Equal(t, 7, len(report.Validators))
+	}
+
+	resp, err := http.Get(server.URL + "/v1/n3/participation?format=csv")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, "text/csv", resp.Header.Get("Content-Type"))
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, 8, len(strings.Split(strings.TrimSpace(string(body)), "\n")))
+
+	var report analytics.ParticipationReport
+	require.Equal(t, http.StatusOK, get(t, server.URL+"/v1/neox/participation", &report))
+	require.Equal(t, 1, report.Blocks+report.Skipped)
+
+	var errRes ErrorResponse
+	require.Equal(t, http.StatusNotFound, get(t, server.URL+"/v1/n3/participation?from=9998", &errRes))
+	require.Equal(t, ReasonNotFound, errRes.Reason)
+	require.Equal(t, http.StatusBadRequest, get(t, server.URL+"/v1/n3/participation?from=10000", &errRes))
+	require.Equal(t, ReasonMalformed, errRes.Reason)
+	require.Equal(t, http.StatusBadRequest, get(t, server.URL+"/v1/n3/participation?from=0&to=200000", &errRes))
+	require.Equal(t, ReasonMalformed, errRes.Reason)
+}
+
+func TestViews(t *testing.T) {
+	server, _, _ := newTestServer(t)
+
+	var report analytics.ViewReport
+	require.Equal(t, http.StatusOK, get(t, server.URL+"/v1/n3/views", &report))
+	require.Equal(t, 1, report.Blocks)
+	require.Equal(t, 0, report.ViewChanges)
+	require.Equal(t, analytics.DefaultFailureThreshold, report.Threshold)
+	require.Equal(t, 1, len(report.Primaries))
+	require.Equal(t, http.StatusOK, get(t, server.URL+"/v1/neox/views?threshold=0.5", &report))
+	require.Equal(t, 1, report.Blocks)
+	require.Equal(t, 0.5, report.Threshold)
+
+	resp, err := http.Get(server.URL + "/v1/n3/views?format=csv")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, "text/csv", resp.Header.Get("Content-Type"))
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(strings.Split(strings.TrimSpace(string(body)), "\n")))
+
+	var errRes ErrorResponse
+	for _, threshold := range []string{"x", "-0.1", "1.5", "NaN"} {
+		require.Equal(t, http.StatusBadRequest, get(t, server.URL+"/v1/n3/views?threshold="+threshold, &errRes))
+		require.Equal(t, ReasonMalformed, errRes.Reason)
+	}
+	require.Equal(t, http.StatusNotFound, get(t, server.URL+"/v1/n3/views?from=9998", &errRes))
+	require.Equal(t, ReasonNotFound, errRes.Reason)
+
+	// A stored header with the primary out of the validator set
+	header := &types.Header{Number: big.NewInt(20), Difficulty: big.NewInt(neox.DiffInTurn), Nonce: types.EncodeNonce(neox.ValidatorsCount)}
+	data, err := rlp.EncodeToBytes(header)
+	require.NoError(t, err)
+	st := store.NewMemory()
+	require.NoError(t, st.Put(20, []store.Header{{Hash: header.Hash().Bytes(), Data: data}}))
+	bad := httptest.NewServer(NewServer(nil, &NeoXBackend{Store: st}))
+	t.Cleanup(bad.Close)
+	require.Equal(t, http.StatusUnprocessableEntity, get(t, bad.URL+"/v1/neox/views", &errRes))
+	require.Equal(t, "primary_out_of_range", errRes.Reason)
+	// In the validator set of a larger consensus
+	larger := httptest.NewServer(NewServer(nil, &NeoXBackend{Store: st, ValidatorsCount: 21}))
+	t.Cleanup(larger.Close)
+	require.Equal(t, http.StatusUnprocessableEntity, get(t, larger.URL+"/v1/neox/views", &errRes))
+	require.NotEqual(t, "primary_out_of_range", errRes.Reason)
+}
@@ -0,0 +1,12 @@
+package api
+
+import "github.com/txhsl/dbft-verifier/reason"
+
+// Reasons of failed requests, ReasonInvalid and the reasons of reason.Of are the
+// reasons of failed verifications.
+const (
+	ReasonMalformed = "malformed_request"
+	ReasonNotFound  = "not_found"
+	ReasonInternal  = "internal_error"
+	ReasonInvalid   = reason.Invalid
+)
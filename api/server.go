@@ -0,0 +1,470 @@
+// Package api serves header and proof bundle verification over HTTP with JSON
+// responses, backed by the light clients and header stores of synced chains.
+//
+// Endpoints, {chain} is n3 or neox:
+//
+//	POST /v1/{chain}/verify         verify {"parent": header, "header": header}
+//	GET  /v1/{chain}/head           the verified head
+//	GET  /v1/{chain}/headers/{id}   a verified header by decimal height or 0x hash
+//	GET  /v1/{chain}/stream         Server-Sent Events of verified headers, see Event
+//	GET  /v1/{chain}/participation  validator participation, ?from=&to=&format=json|csv
+//	GET  /v1/{chain}/views          view changes and failing primaries, ?from=&to=&threshold=&format=
+//	POST /v1/bundles/verify         verify a JSON or binary (application/octet-stream) bundle
+//
+// Headers are in getblockheader (verbose) and eth_getBlockByNumber formats.
+// Verification failures are returned with a machine-readable reason.
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/nspcc-dev/neo-go/pkg/core/block"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/txhsl/dbft-verifier/bundle"
+	"github.com/txhsl/dbft-verifier/reason"
+	"github.com/txhsl/dbft-verifier/store"
+	"github.com/txhsl/dbft-verifier/syncer"
+	n3 "github.com/txhsl/n3-dbft-verifier"
+	neox "github.com/txhsl/neox-dbft-verifier"
+)
+
+// maxBodySize limits request bodies.
+const maxBodySize = 4 << 20
+
+// N3Backend is the verified N3 chain served by Server.
+type N3Backend struct {
+	Client *n3.LightClient
+	Store  *store.Store
+	// StateRootInHeader is the StateRootInHeader setting of the network, headers
+	// commit to the state root and bundles may carry state proofs then.
+	StateRootInHeader bool
+}
+
+// decode decodes a stored header.
+func (b *N3Backend) decode(data []byte) (*block.Header, error) {
+	return syncer.DecodeN3HeaderStateRoot(data, b.StateRootInHeader)
+}
+
+// NeoXBackend is the verified Neo X chain served by Server.
+type NeoXBackend struct {
+	Client *neox.LightClient
+	Store  *store.Store
+	// ValidatorsCount is the number of validators of headers that don't reveal the
+	// validator set, e.g. of a verified consensus list, neox.ValidatorsCount if 0.
+	ValidatorsCount int
+}
+
+func (b *NeoXBackend) validatorsCount() int {
+	if b.ValidatorsCount == 0 {
+		return neox.ValidatorsCount
+	}
+	return b.ValidatorsCount
+}
+
+// Server is the HTTP verification service, chains without a backend are not served.
+type Server struct {
+	n3       *N3Backend
+	neoX     *NeoXBackend
+	n3Feed   *feed
+	neoXFeed *feed
+	mux      *http.ServeMux
+
+	lock           sync.RWMutex
+	onEquivocation []func(chain string, e Event)
+	onRejection    []func(chain, reason string)
+}
+
+// VerifyRequest is the body of header verification requests.
+type VerifyRequest struct {
+	Parent json.RawMessage `json:"parent"`
+	Header json.RawMessage `json:"header"`
+}
+
+// VerifyResponse is the result of a verification, Reason and Error are set if it's
+// not valid. Conflicting is the hash of the verified header of the same height if a
+// valid child of the verified parent differs from it, that is an equivocation.
+type VerifyResponse struct {
+	Valid       bool   `json:"valid"`
+	Reason      string `json:"reason,omitempty"`
+	Error       string `json:"error,omitempty"`
+	Conflicting string `json:"conflicting,omitempty"`
+}
+
+// HeaderResponse is a verified header.
+type HeaderResponse struct {
+	Height uint64          `json:"height"`
+	Hash   string          `json:"hash"`
+	Header json.RawMessage `json:"header"`
+}
+
+// ErrorResponse is returned for requests that can't be served.
+type ErrorResponse struct {
+	Reason string `json:"reason"`
+	Error  string `json:"error"`
+}
+
+// NewServer returns Server of the given backends, either may be nil.
+func NewServer(n3Backend *N3Backend, neoXBackend *NeoXBackend) *Server {
+	s := &Server{n3: n3Backend, neoX: neoXBackend, mux: http.NewServeMux()}
+	if n3Backend != nil {
+		s.n3Feed = newFeed(n3Backend.Store, n3Backend.describe)
+		if n3Backend.Client != nil {
+			// Callbacks of an update run in order, the verification before the change
+			var verified util.Uint256
+			n3Backend.Client.OnVerification(func(v n3.Verification) {
+				if v.Err == nil {
+					verified = v.Header.Hash()
+				}
+			})
+			n3Backend.Client.OnValidatorSetChange(func(c n3.ValidatorSetChange) {
+				s.n3Feed.change(n3Change(c, verified))
+			})
+		}
+		s.mux.HandleFunc("POST /v1/n3/verify", s.verifyN3)
+		s.mux.HandleFunc("GET /v1/n3/head", s.n3Head)
+		s.mux.HandleFunc("GET /v1/n3/headers/{id}", s.n3Header)
+		s.mux.HandleFunc("GET /v1/n3/stream", s.n3Feed.serveHTTP)
+		s.mux.HandleFunc("GET /v1/n3/participation", s.n3Participation)
+		s.mux.HandleFunc("GET /v1/n3/views", s.n3Views)
+	}
+	if neoXBackend != nil {
+		s.neoXFeed = newFeed(neoXBackend.Store, describeNeoX)
+		if neoXBackend.Client != nil {
+			var verified common.Hash
+			neoXBackend.Client.OnVerification(func(v neox.Verification) {
+				if v.Err == nil {
+					verified = v.Header.Hash()
+				}
+			})
+			neoXBackend.Client.OnValidatorSetChange(func(c neox.ValidatorSetChange) {
+				s.neoXFeed.change(neoXChange(c, verified))
+			})
+		}
+		s.mux.HandleFunc("POST /v1/neox/verify", s.verifyNeoX)
+		s.mux.HandleFunc("GET /v1/neox/head", s.neoXHead)
+		s.mux.HandleFunc("GET /v1/neox/headers/{id}", s.neoXHeader)
+		s.mux.HandleFunc("GET /v1/neox/stream", s.neoXFeed.serveHTTP)
+		s.mux.HandleFunc("GET /v1/neox/participation", s.neoXParticipation)
+		s.mux.HandleFunc("GET /v1/neox/views", s.neoXViews)
+	}
+	s.mux.HandleFunc("POST /v1/bundles/verify", s.verifyBundle)
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// Notify makes streams send headers stored since the last check, otherwise they poll
+// the stores every second.
+func (s *Server) Notify() {
+	for _, f := range []*feed{s.n3Feed, s.neoXFeed} {
+		if f != nil {
+			f.notify()
+		}
+	}
+}
+
+// OnEquivocation registers f to be called on every equivocation found, chain is n3 or
+// neox. Callbacks run synchronously in the verification request.
+func (s *Server) OnEquivocation(f func(chain string, e Event)) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.onEquivocation = append(s.onEquivocation, f)
+}
+
+// OnRejection registers f to be called on every header or bundle failing verification,
+// chain is n3 or neox and reason is the reason of the response. Callbacks run
+// synchronously in the verification request.
+func (s *Server) OnRejection(f func(chain, reason string)) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.onRejection = append(s.onRejection, f)
+}
+
+// rejection reports the verification error of chain to the callbacks.
+func (s *Server) rejection(chain string, err error) {
+	s.lock.RLock()
+	callbacks := s.onRejection
+	s.lock.RUnlock()
+	for _, cb := range callbacks {
+		cb(chain, reason.Of(err))
+	}
+}
+
+// equivocation publishes e to the streams of chain and the callbacks.
+func (s *Server) equivocation(chain string, f *feed, e Event) {
+	f.publish(e)
+	s.lock.RLock()
+	callbacks := s.onEquivocation
+	s.lock.RUnlock()
+	for _, cb := range callbacks {
+		cb(chain, e)
+	}
+}
+
+func (s *Server) verifyN3(w http.ResponseWriter, r *http.Request) {
+	var req VerifyRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	parent, current := new(block.Header), new(block.Header)
+	if err := parent.UnmarshalJSON(req.Parent); err != nil {
+		writeError(w, http.StatusBadRequest, ReasonMalformed, err)
+		return
+	}
+	if err := current.UnmarshalJSON(req.Header); err != nil {
+		writeError(w, http.StatusBadRequest, ReasonMalformed, err)
+		return
+	}
+	if err := n3.CheckUpdateHeader(parent, current, s.n3.Client.Network()); err != nil {
+		s.rejection("n3", err)
+		writeVerify(w, err)
+		return
+	}
+	res := VerifyResponse{Valid: true}
+	// A forged parent can commit to any signers, so only children of the verified
+	// parent are equivocations
+	if !isStored(s.n3.Store, uint64(parent.Index), parent.Hash().BytesBE()) {
+		writeJSON(w, http.StatusOK, res)
+		return
+	}
+	if data, err := s.n3.Store.Get(uint64(current.Index)); err == nil {
+		if verified, err := s.n3.decode(data); err == nil && verified.Hash() != current.Hash() {
+			res.Conflicting = "0x" + verified.Hash().StringLE()
+			s.equivocation("n3", s.n3Feed, Event{
+				Type:        EventEquivocation,
+				Height:      uint64(current.Index),
+				Hash:        "0x" + current.Hash().StringLE(),
+				Conflicting: res.Conflicting,
+			})
+		}
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+func (s *Server) verifyNeoX(w http.ResponseWriter, r *http.Request) {
+	var req VerifyRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	parent, current := new(types.Header), new(types.Header)
+	if err := parent.UnmarshalJSON(req.Parent); err != nil {
+		writeError(w, http.StatusBadRequest, ReasonMalformed, err)
+		return
+	}
+	if err := current.UnmarshalJSON(req.Header); err != nil {
+		writeError(w, http.StatusBadRequest, ReasonMalformed, err)
+		return
+	}
+	if err := neox.CheckUpdateHeader(s.neoX.Client.Config(), parent, current); err != nil {
+		s.rejection("neox", err)
+		writeVerify(w, err)
+		return
+	}
+	res := VerifyResponse{Valid: true}
+	if !isStored(s.neoX.Store, parent.Number.Uint64(), parent.Hash().Bytes()) {
+		writeJSON(w, http.StatusOK, res)
+		return
+	}
+	if data, err := s.neoX.Store.Get(current.Number.Uint64()); err == nil {
+		if verified, err := syncer.DecodeNeoXHeader(data); err == nil && verified.Hash() != current.Hash() {
+			res.Conflicting = verified.Hash().Hex()
+			s.equivocation("neox", s.neoXFeed, Event{
+				Type:        EventEquivocation,
+				Height:      current.Number.Uint64(),
+				Hash:        current.Hash().Hex(),
+				Conflicting: res.Conflicting,
+			})
+		}
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+func (s *Server) verifyBundle(w http.ResponseWriter, r *http.Request) {
+	b := new(bundle.ProofBundle)
+	if r.Header.Get("Content-Type") == "application/octet-stream" {
+		data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodySize))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, ReasonMalformed, err)
+			return
+		}
+		if err := b.UnmarshalBinary(data); err != nil {
+			writeError(w, http.StatusBadRequest, ReasonMalformed, err)
+			return
+		}
+	} else if !decodeBody(w, r, b) {
+		return
+	}
+	// Only the anchor is needed from the stores
+	trust := bundle.NewTrustStore(0, neox.DefaultChainConfig)
+	chain := "n3"
+	switch {
+	case b.Chain == bundle.ChainN3 && s.n3 != nil:
+		trust.N3Network = s.n3.Client.Network()
+		trust.N3StateRootInHeader = s.n3.StateRootInHeader
+		if data, err := getByHash(s.n3.Store, util.Uint256(b.Anchor).Reverse().BytesBE()); err == nil {
+			if header, err := s.n3.decode(data); err == nil {
+				trust.AddN3Header(header)
+			}
+		}
+	case b.Chain == bundle.ChainNeoX && s.neoX != nil:
+		chain = "neox"
+		trust.NeoXConfig = s.neoX.Client.Config()
+		if data, err := getByHash(s.neoX.Store, b.Anchor[:]); err == nil {
+			if header, err := syncer.DecodeNeoXHeader(data); err == nil {
+				trust.AddNeoXHeader(header)
+			}
+		}
+	default:
+		writeError(w, http.StatusNotFound, ReasonNotFound, errors.New("chain is not served"))
+		return
+	}
+	err := b.Verify(trust)
+	if err != nil {
+		s.rejection(chain, err)
+	}
+	writeVerify(w, err)
+}
+
+func (s *Server) n3Head(w http.ResponseWriter, r *http.Request) {
+	writeN3Header(w, s.n3.Client.Head())
+}
+
+func (s *Server) neoXHead(w http.ResponseWriter, r *http.Request) {
+	writeNeoXHeader(w, s.neoX.Client.Head())
+}
+
+func (s *Server) n3Header(w http.ResponseWriter, r *http.Request) {
+	data, ok := getHeader(w, s.n3.Store, r.PathValue("id"), func(hash common.Hash) []byte {
+		// N3 hashes are displayed little-endian and stored big-endian
+		return util.Uint256(hash).Reverse().BytesBE()
+	})
+	if !ok {
+		return
+	}
+	header, err := s.n3.decode(data)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, ReasonInternal, err)
+		return
+	}
+	writeN3Header(w, header)
+}
+
+func (s *Server) neoXHeader(w http.ResponseWriter, r *http.Request) {
+	data, ok := getHeader(w, s.neoX.Store, r.PathValue("id"), func(hash common.Hash) []byte {
+		return hash[:]
+	})
+	if !ok {
+		return
+	}
+	header, err := syncer.DecodeNeoXHeader(data)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, ReasonInternal, err)
+		return
+	}
+	writeNeoXHeader(w, header)
+}
+
+// getHeader returns the stored header by decimal height or 0x-prefixed hash, it
+// writes the error response on failure.
+func getHeader(w http.ResponseWriter, st *store.Store, id string, key func(common.Hash) []byte) ([]byte, bool) {
+	var (
+		data []byte
+		err  error
+	)
+	if strings.HasPrefix(id, "0x") {
+		if len(id) != 2+2*common.HashLength {
+			writeError(w, http.StatusBadRequest, ReasonMalformed, errors.New("malformed hash"))
+			return nil, false
+		}
+		data, err = getByHash(st, key(common.HexToHash(id)))
+	} else {
+		height, perr := strconv.ParseUint(id, 10, 64)
+		if perr != nil {
+			writeError(w, http.StatusBadRequest, ReasonMalformed, perr)
+			return nil, false
+		}
+		data, err = st.Get(height)
+	}
+	if errors.Is(err, store.ErrNotFound) {
+		writeError(w, http.StatusNotFound, ReasonNotFound, err)
+		return nil, false
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, ReasonInternal, err)
+		return nil, false
+	}
+	return data, true
+}
+
+// isStored reports whether the header with hash is the verified header at height.
+func isStored(st *store.Store, height uint64, hash []byte) bool {
+	stored, _, err := st.GetByHash(hash)
+	return err == nil && stored == height
+}
+
+func getByHash(st *store.Store, hash []byte) ([]byte, error) {
+	_, data, err := st.GetByHash(hash)
+	return data, err
+}
+
+func writeN3Header(w http.ResponseWriter, header *block.Header) {
+	data, err := header.MarshalJSON()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, ReasonInternal, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, HeaderResponse{
+		Height: uint64(header.Index),
+		Hash:   "0x" + header.Hash().StringLE(),
+		Header: data,
+	})
+}
+
+func writeNeoXHeader(w http.ResponseWriter, header *types.Header) {
+	data, err := header.MarshalJSON()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, ReasonInternal, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, HeaderResponse{
+		Height: header.Number.Uint64(),
+		Hash:   header.Hash().Hex(),
+		Header: data,
+	})
+}
+
+func decodeBody(w http.ResponseWriter, r *http.Request, v any) bool {
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize)).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, ReasonMalformed, err)
+		return false
+	}
+	return true
+}
+
+func writeVerify(w http.ResponseWriter, err error) {
+	if err != nil {
+		writeJSON(w, http.StatusOK, VerifyResponse{Reason: reason.Of(err), Error: err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, VerifyResponse{Valid: true})
+}
+
+func writeError(w http.ResponseWriter, status int, reason string, err error) {
+	writeJSON(w, status, ErrorResponse{Reason: reason, Error: err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,260 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/nspcc-dev/neo-go/pkg/core/block"
+	"github.com/nspcc-dev/neo-go/pkg/core/mpt"
+	"github.com/nspcc-dev/neo-go/pkg/core/storage"
+	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/stretchr/testify/require"
+	"github.com/txhsl/dbft-verifier/bundle"
+	"github.com/txhsl/dbft-verifier/store"
+	"github.com/txhsl/dbft-verifier/syncer"
+	n3 "github.com/txhsl/n3-dbft-verifier"
+	neox "github.com/txhsl/neox-dbft-verifier"
+)
+
+func loadRaws(t *testing.T, path string) []json.RawMessage {
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var raws []json.RawMessage
+	require.NoError(t, json.Unmarshal(data, &raws))
+	return raws
+}
+
+func newTestServer(t *testing.T) (*httptest.Server, []json.RawMessage, []json.RawMessage) {
+	s, n3Raws, neoXRaws := newTestAPI(t)
+	server := httptest.NewServer(s)
+	t.Cleanup(server.Close)
+	return server, n3Raws, neoXRaws
+}
+
+func newTestAPI(t *testing.T) (*Server, []json.RawMessage, []json.RawMessage) {
+	n3Raws := loadRaws(t, "../bundle/testdata/n3_headers.json")
+	neoXRaws := loadRaws(t, "../bundle/testdata/neox_headers.json")
+	n3Trusted := new(block.Header)
+	require.NoError(t, n3Trusted.UnmarshalJSON(n3Raws[0]))
+	neoXTrusted := new(types.Header)
+	require.NoError(t, neoXTrusted.UnmarshalJSON(neoXRaws[0]))
+
+	n3Store, neoXStore := store.NewMemory(), store.NewMemory()
+	n3Client, err := syncer.ResumeN3(n3Store, n3Trusted, 860833102)
+	require.NoError(t, err)
+	neoXClient, err := syncer.ResumeNeoX(neoXStore, neoXTrusted, neox.DefaultChainConfig)
+	require.NoError(t, err)
+	s := NewServer(
+		&N3Backend{Client: n3Client, Store: n3Store},
+		&NeoXBackend{Client: neoXClient, Store: neoXStore},
+	)
+	return s, n3Raws, neoXRaws
+}
+
+func post(t *testing.T, url, contentType string, body []byte, v any) int {
+	resp, err := http.Post(url, contentType, bytes.NewReader(body))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(v))
+	return resp.StatusCode
+}
+
+func get(t *testing.T, url string, v any) int {
+	resp, err := http.Get(url)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(v))
+	return resp.StatusCode
+}
+
+func verifyBody(t *testing.T, parent, header json.RawMessage) []byte {
+	body, err := json.Marshal(VerifyRequest{Parent: parent, Header: header})
+	require.NoError(t, err)
+	return body
+}
+
+func TestVerify(t *testing.T) {
+	s, n3Raws, neoXRaws := newTestAPI(t)
+	var rejections []string
+	s.OnRejection(func(chain, reason string) {
+		rejections = append(rejections, chain+" "+reason)
+	})
+	server := httptest.NewServer(s)
+	t.Cleanup(server.Close)
+
+	var res VerifyResponse
+	require.Equal(t, http.StatusOK, post(t, server.URL+"/v1/n3/verify", "application/json", verifyBody(t, n3Raws[0], n3Raws[1]), &res))
+	require.Equal(t, VerifyResponse{Valid: true}, res)
+	res = VerifyResponse{}
+	require.Equal(t, http.StatusOK, post(t, server.URL+"/v1/n3/verify", "application/json", verifyBody(t, n3Raws[1], n3Raws[0]), &res))
+	require.Equal(t, false, res.Valid)
+	require.Equal(t, "prev_hash_mismatch", res.Reason)
+
+	res = VerifyResponse{}
+	require.Equal(t, http.StatusOK, post(t, server.URL+"/v1/neox/verify", "application/json", verifyBody(t, neoXRaws[0], neoXRaws[1]), &res))
+	require.Equal(t, VerifyResponse{Valid: true}, res)
+	res = VerifyResponse{}
+	require.Equal(t, http.StatusOK, post(t, server.URL+"/v1/neox/verify", "application/json", verifyBody(t, neoXRaws[0], neoXRaws[2]), &res))
+	require.Equal(t, false, res.Valid)
+	require.Equal(t, "parent_hash_mismatch", res.Reason)
+
+	var errRes ErrorResponse
+	require.Equal(t, http.StatusBadRequest, post(t, server.URL+"/v1/n3/verify", "application/json", []byte(`{"parent":1}`), &errRes))
+	require.Equal(t, ReasonMalformed, errRes.Reason)
+	require.Equal(t, []string{"n3 prev_hash_mismatch", "neox parent_hash_mismatch"}, rejections)
+}
+
+func TestHeaders(t *testing.T) {
+	server, n3Raws, neoXRaws := newTestServer(t)
+	n3Trusted := new(block.Header)
+	require.NoError(t, n3Trusted.UnmarshalJSON(n3Raws[0]))
+	neoXTrusted := new(types.Header)
+	require.NoError(t, neoXTrusted.UnmarshalJSON(neoXRaws[0]))
+
+	for _, path := range []string{"/v1/n3/head", "/v1/n3/headers/9999", "/v1/n3/headers/0x" + n3Trusted.Hash().StringLE()} {
+		var res HeaderResponse
+		require.Equal(t, http.StatusOK, get(t, server.URL+path, &res))
+		require.Equal(t, uint64(9999), res.Height)
+		require.Equal(t, "0x"+n3Trusted.Hash().StringLE(), res.Hash)
+		header := new(block.Header)
+		require.NoError(t, header.UnmarshalJSON(res.Header))
+		require.Equal(t, n3Trusted.Hash(), header.Hash())
+	}
+	for _, path := range []string{"/v1/neox/head", "/v1/neox/headers/2087998", "/v1/neox/headers/" + neoXTrusted.Hash().Hex()} {
+		var res HeaderResponse
+		require.Equal(t, http.StatusOK, get(t, server.URL+path, &res))
+		require.Equal(t, neoXTrusted.Number.Uint64(), res.Height)
+		require.Equal(t, neoXTrusted.Hash().Hex(), res.Hash)
+	}
+
+	var errRes ErrorResponse
+	require.Equal(t, http.StatusNotFound, get(t, server.URL+"/v1/n3/headers/10000", &errRes))
+	require.Equal(t, ReasonNotFound, errRes.Reason)
+	require.Equal(t, http.StatusNotFound, get(t, server.URL+"/v1/neox/headers/"+common.Hash{1}.Hex(), &errRes))
+	require.Equal(t, ReasonNotFound, errRes.Reason)
+	require.Equal(t, http.StatusBadRequest, get(t, server.URL+"/v1/neox/headers/0x01", &errRes))
+	require.Equal(t, ReasonMalformed, errRes.Reason)
+}
+
+func TestVerifyBundle(t *testing.T) {
+	server, n3Raws, neoXRaws := newTestServer(t)
+	n3Headers := make([]*block.Header, 2)
+	for i := range n3Headers {
+		n3Headers[i] = new(block.Header)
+		require.NoError(t, n3Headers[i].UnmarshalJSON(n3Raws[i]))
+	}
+	w := io.NewBufBinWriter()
+	n3Headers[1].EncodeBinary(w.BinWriter)
+	require.NoError(t, w.Err)
+	b := &bundle.ProofBundle{
+		Version: bundle.Version,
+		Chain:   bundle.ChainN3,
+		Anchor:  common.Hash(n3Headers[0].Hash().Reverse()),
+		Headers: []hexutil.Bytes{w.Bytes()},
+	}
+	body, err := json.Marshal(b)
+	require.NoError(t, err)
+	var res VerifyResponse
+	require.Equal(t, http.StatusOK, post(t, server.URL+"/v1/bundles/verify", "application/json", body, &res))
+	require.Equal(t, VerifyResponse{Valid: true}, res)
+
+	// Anchored in a header that isn't stored
+	b.Anchor = common.Hash(n3Headers[1].Hash().Reverse())
+	b.Headers = nil
+	body, err = json.Marshal(b)
+	require.NoError(t, err)
+	res = VerifyResponse{}
+	require.Equal(t, http.StatusOK, post(t, server.URL+"/v1/bundles/verify", "application/json", body, &res))
+	require.Equal(t, "untrusted_anchor", res.Reason)
+
+	neoXHeaders := make([]*types.Header, 2)
+	for i := range neoXHeaders {
+		neoXHeaders[i] = new(types.Header)
+		require.NoError(t, neoXHeaders[i].UnmarshalJSON(neoXRaws[i]))
+	}
+	data, err := rlp.EncodeToBytes(neoXHeaders[1])
+	require.NoError(t, err)
+	b = &bundle.ProofBundle{
+		Version: bundle.Version,
+		Chain:   bundle.ChainNeoX,
+		Anchor:  neoXHeaders[0].Hash(),
+		Headers: []hexutil.Bytes{data},
+	}
+	body, err = b.MarshalBinary()
+	require.NoError(t, err)
+	res = VerifyResponse{}
+	require.Equal(t, http.StatusOK, post(t, server.URL+"/v1/bundles/verify", "application/octet-stream", body, &res))
+	require.Equal(t, VerifyResponse{Valid: true}, res)
+
+	// Invalid proof
+	b.Proof = bundle.Proof{Kind: bundle.ProofNeoXTx}
+	body, err = b.MarshalBinary()
+	require.NoError(t, err)
+	res = VerifyResponse{}
+	require.Equal(t, http.StatusOK, post(t, server.URL+"/v1/bundles/verify", "application/octet-stream", body, &res))
+	require.Equal(t, "invalid_proof", res.Reason)
+
+	var errRes ErrorResponse
+	require.Equal(t, http.StatusBadRequest, post(t, server.URL+"/v1/bundles/verify", "application/octet-stream", []byte{1, 2}, &errRes))
+	require.Equal(t, ReasonMalformed, errRes.Reason)
+}
+
+func TestVerifyN3StateBundle(t *testing.T) {
+	trie := mpt.NewTrie(nil, mpt.ModeAll, storage.NewMemCachedStore(storage.NewMemoryStore()))
+	require.NoError(t, trie.Put([]byte{0x01, 0x02}, []byte("value")))
+	trie.Flush(0)
+	proof, err := trie.GetProof([]byte{0x01, 0x02})
+	require.NoError(t, err)
+
+	// Network with state roots in headers
+	v := newTestValidators(t)
+	anchor := &block.Header{StateRootEnabled: true, Timestamp: 1628062127819, NextConsensus: v.hash(t)}
+	st := store.NewMemory()
+	client, err := syncer.ResumeN3(st, anchor, testNetwork)
+	require.NoError(t, err)
+	header := &block.Header{
+		StateRootEnabled: true,
+		PrevHash:         anchor.Hash(),
+		Timestamp:        anchor.Timestamp + 15000,
+		Index:            1,
+		NextConsensus:    anchor.NextConsensus,
+		PrevStateRoot:    trie.StateRoot(),
+	}
+	sigs := make([][]byte, len(v.privs))
+	for i := range sigs {
+		sigs[i] = v.privs[i].SignHashable(testNetwork, header)
+	}
+	header.Script, _, err = n3.BuildWitness(v.pubs, sigs)
+	require.NoError(t, err)
+	w := io.NewBufBinWriter()
+	header.EncodeBinary(w.BinWriter)
+	require.NoError(t, w.Err)
+	b := &bundle.ProofBundle{
+		Version: bundle.Version,
+		Chain:   bundle.ChainN3,
+		Anchor:  common.Hash(anchor.Hash().Reverse()),
+		Headers: []hexutil.Bytes{w.Bytes()},
+		Proof:   bundle.Proof{Kind: bundle.ProofN3State, Key: []byte{0x01, 0x02}, Value: []byte("value")},
+	}
+	for _, node := range proof {
+		b.Proof.Nodes = append(b.Proof.Nodes, node)
+	}
+	body, err := json.Marshal(b)
+	require.NoError(t, err)
+
+	for _, stateRootInHeader := range []bool{true, false} {
+		server := httptest.NewServer(NewServer(&N3Backend{Client: client, Store: st, StateRootInHeader: stateRootInHeader}, nil))
+		var res VerifyResponse
+		require.Equal(t, http.StatusOK, post(t, server.URL+"/v1/bundles/verify", "application/json", body, &res))
+		require.Equal(t, stateRootInHeader, res.Valid)
+		server.Close()
+	}
+}
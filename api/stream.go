@@ -0,0 +1,332 @@
+package api
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm"
+	"github.com/txhsl/dbft-verifier/store"
+	"github.com/txhsl/dbft-verifier/syncer"
+	n3 "github.com/txhsl/n3-dbft-verifier"
+	neox "github.com/txhsl/neox-dbft-verifier"
+)
+
+// Stream event types.
+const (
+	// EventHeader is a verified header.
+	EventHeader = "header"
+	// EventValidators is a change of the next consensus commitment reported by the
+	// light client of the backend, so only changes verified while the server runs
+	// are streamed. It precedes the header event of the same height unless the
+	// header is sent before the client reports the change.
+	EventValidators = "validators"
+	// EventEquivocation is a header submitted for verification that is valid on top of
+	// the verified parent but differs from the verified header of its height.
+	EventEquivocation = "equivocation"
+)
+
+// streamPollInterval is the interval streams check the store at without Notify.
+const streamPollInterval = time.Second
+
+// eventBuffer is the number of equivocation and validators events queued per stream,
+// newer ones are dropped for slow clients.
+const eventBuffer = 16
+
+// Event is a stream event.
+type Event struct {
+	Type   string `json:"type"`
+	Height uint64 `json:"height"`
+	Hash   string `json:"hash"`
+	// Commitment is the commitment to the next consensus, NextConsensus of N3 and
+	// MixDigest of Neo X, Previous is the replaced one of validators events.
+	Commitment string `json:"commitment,omitempty"`
+	Previous   string `json:"previous,omitempty"`
+	// Scheme is how the header is signed, it's set for header events.
+	Scheme *Scheme `json:"scheme,omitempty"`
+	// Validators are the signers of the header of validators events, N3 public keys
+	// and Neo X addresses or global threshold key.
+	Validators []string `json:"validators,omitempty"`
+	// Conflicting is the hash of the verified header of equivocation events.
+	Conflicting string `json:"conflicting,omitempty"`
+}
+
+// Scheme is the consensus scheme metadata of a header.
+type Scheme struct {
+	// Name is multisig for N3 and ecdsa or threshold for Neo X.
+	Name string `json:"name"`
+	// Version is the Neo X extra version.
+	Version *byte `json:"version,omitempty"`
+	// M of N signatures are required, it's unknown for threshold schemes.
+	M int `json:"m,omitempty"`
+	N int `json:"n,omitempty"`
+}
+
+// feed streams the verified headers of a chain from its store.
+type feed struct {
+	store *store.Store
+	// describe returns the header event of the stored header data.
+	describe func(data []byte) (Event, error)
+
+	lock sync.Mutex
+	wake chan struct{}
+	subs map[chan Event]struct{}
+	// changes are the validators events reported by the light client by height.
+	changes map[uint64]Event
+}
+
+func newFeed(st *store.Store, describe func([]byte) (Event, error)) *feed {
+	return &feed{
+		store:    st,
+		describe: describe,
+		wake:     make(chan struct{}),
+		subs:     make(map[chan Event]struct{}),
+		changes:  make(map[uint64]Event),
+	}
+}
+
+// change records the validators event e and sends it to the current streams, they
+// send it before the header of its height or at once if they've sent the header.
+func (f *feed) change(e Event) {
+	f.lock.Lock()
+	f.changes[e.Height] = e
+	f.lock.Unlock()
+	f.publish(e)
+}
+
+// changeAt returns the validators event of height if any.
+func (f *feed) changeAt(height uint64) (Event, bool) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	e, ok := f.changes[height]
+	return e, ok
+}
+
+// notify wakes the streams up to check the store.
+func (f *feed) notify() {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	close(f.wake)
+	f.wake = make(chan struct{})
+}
+
+func (f *feed) waiter() <-chan struct{} {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return f.wake
+}
+
+// publish sends e to the current streams.
+func (f *feed) publish(e Event) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	for ch := range f.subs {
+		select {
+		case ch <- e:
+		default:
+		}
+	}
+}
+
+func (f *feed) subscribe() chan Event {
+	ch := make(chan Event, eventBuffer)
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.subs[ch] = struct{}{}
+	return ch
+}
+
+func (f *feed) unsubscribe(ch chan Event) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	delete(f.subs, ch)
+}
+
+// serveHTTP streams events as Server-Sent Events. Header events have the height as id,
+// so the stream is resumed after Last-Event-ID, from the from query parameter or it
+// starts after the current head.
+func (f *feed) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, ReasonInternal, errors.New("streaming is not supported"))
+		return
+	}
+	head, _, err := f.store.Head()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, ReasonInternal, err)
+		return
+	}
+	next := head + 1
+	if id := r.Header.Get("Last-Event-ID"); id != "" {
+		last, err := strconv.ParseUint(id, 10, 64)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, ReasonMalformed, err)
+			return
+		}
+		next = last + 1
+	} else if from := r.URL.Query().Get("from"); from != "" {
+		next, err = strconv.ParseUint(from, 10, 64)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, ReasonMalformed, err)
+			return
+		}
+	}
+	if next <= head {
+		// Headers below the trusted one aren't stored
+		if _, err := f.store.Get(next); err != nil {
+			writeError(w, http.StatusNotFound, ReasonNotFound, err)
+			return
+		}
+	}
+	// Validators events are sent once, before the header of their height or once
+	// published if the header is sent
+	var sent uint64
+	events := f.subscribe()
+	defer f.unsubscribe(events)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+	for {
+		wake := f.waiter()
+		head, _, err := f.store.Head()
+		if err != nil {
+			return
+		}
+		for ; next <= head; next++ {
+			data, err := f.store.Get(next)
+			if err != nil {
+				return
+			}
+			e, err := f.describe(data)
+			if err != nil {
+				return
+			}
+			if change, ok := f.changeAt(next); ok && change.Hash == e.Hash {
+				if err := writeEvent(w, "", change); err != nil {
+					return
+				}
+				sent = next
+			}
+			if err := writeEvent(w, strconv.FormatUint(e.Height, 10), e); err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+		select {
+		case <-r.Context().Done():
+			return
+		case e := <-events:
+			if e.Type == EventValidators && (e.Height >= next || e.Height <= sent) {
+				continue
+			}
+			if e.Type == EventValidators {
+				sent = e.Height
+			}
+			if err := writeEvent(w, "", e); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-wake:
+		case <-time.After(streamPollInterval):
+		}
+	}
+}
+
+func writeEvent(w http.ResponseWriter, id string, e Event) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	if id != "" {
+		if _, err := fmt.Fprintf(w, "id: %s\n", id); err != nil {
+			return err
+		}
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.Type, data)
+	return err
+}
+
+func (b *N3Backend) describe(data []byte) (Event, error) {
+	header, err := b.decode(data)
+	if err != nil {
+		return Event{}, err
+	}
+	e := Event{
+		Type:       EventHeader,
+		Height:     uint64(header.Index),
+		Hash:       "0x" + header.Hash().StringLE(),
+		Commitment: "0x" + header.NextConsensus.StringLE(),
+		Scheme:     &Scheme{Name: "multisig"},
+	}
+	if m, pubs, ok := vm.ParseMultiSigContract(header.Script.VerificationScript); ok {
+		e.Scheme.M, e.Scheme.N = m, len(pubs)
+	}
+	return e, nil
+}
+
+func describeNeoX(data []byte) (Event, error) {
+	header, err := syncer.DecodeNeoXHeader(data)
+	if err != nil {
+		return Event{}, err
+	}
+	e := Event{
+		Type:       EventHeader,
+		Height:     header.Number.Uint64(),
+		Hash:       header.Hash().Hex(),
+		Commitment: header.MixDigest.Hex(),
+	}
+	extra, err := neox.ParseExtra(header.Extra)
+	if err != nil {
+		// Verified headers have well-formed extra
+		return e, nil
+	}
+	e.Scheme = &Scheme{Version: &extra.Version}
+	if extra.GlobalKey != nil {
+		e.Scheme.Name = "threshold"
+		return e, nil
+	}
+	e.Scheme.Name = "ecdsa"
+	e.Scheme.M, e.Scheme.N = len(extra.Signatures), len(extra.Addresses)
+	return e, nil
+}
+
+// n3Change returns the validators event of a change reported by the N3 client.
+func n3Change(c n3.ValidatorSetChange, hash util.Uint256) Event {
+	e := Event{
+		Type:       EventValidators,
+		Height:     uint64(c.Height),
+		Hash:       "0x" + hash.StringLE(),
+		Commitment: "0x" + c.New.StringLE(),
+		Previous:   "0x" + c.Old.StringLE(),
+	}
+	for _, key := range c.Keys {
+		e.Validators = append(e.Validators, hex.EncodeToString(key.Bytes()))
+	}
+	return e
+}
+
+// neoXChange returns the validators event of a change reported by the Neo X client.
+func neoXChange(c neox.ValidatorSetChange, hash common.Hash) Event {
+	e := Event{
+		Type:       EventValidators,
+		Height:     c.Height,
+		Hash:       hash.Hex(),
+		Commitment: c.New.Hex(),
+		Previous:   c.Old.Hex(),
+	}
+	if c.GlobalKey != nil {
+		e.Validators = []string{"0x" + hex.EncodeToString(c.GlobalKey)}
+	}
+	for _, addr := range c.Addresses {
+		e.Validators = append(e.Validators, addr.Hex())
+	}
+	return e
+}
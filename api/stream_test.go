@@ -0,0 +1,268 @@
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/nspcc-dev/neo-go/pkg/core/block"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/stretchr/testify/require"
+	"github.com/txhsl/dbft-verifier/store"
+	"github.com/txhsl/dbft-verifier/syncer"
+	n3 "github.com/txhsl/n3-dbft-verifier"
+)
+
+const testNetwork = 860833102
+
+type testValidators struct {
+	privs []*keys.PrivateKey
+	pubs  keys.PublicKeys
+}
+
+func newTestValidators(t *testing.T) *testValidators {
+	v := &testValidators{privs: make([]*keys.PrivateKey, n3.DefaultLimits.ValidatorsCount), pubs: make(keys.PublicKeys, n3.DefaultLimits.ValidatorsCount)}
+	for i := range v.privs {
+		priv, err := keys.NewPrivateKey()
+		require.NoError(t, err)
+		v.privs[i], v.pubs[i] = priv, priv.PublicKey()
+	}
+	return v
+}
+
+func (v *testValidators) hash(t *testing.T) util.Uint160 {
+	sigs := make([][]byte, len(v.pubs))
+	for i := range sigs {
+		sigs[i] = make([]byte, n3.SignatureLen)
+	}
+	_, h, err := n3.BuildWitness(v.pubs, sigs)
+	require.NoError(t, err)
+	return h
+}
+
+// child returns a child of parent signed by v.
+func (v *testValidators) child(t *testing.T, parent *block.Header, nextConsensus util.Uint160, timestamp uint64) *block.Header {
+	header := &block.Header{
+		PrevHash:      parent.Hash(),
+		Timestamp:     timestamp,
+		Index:         parent.Index + 1,
+		NextConsensus: nextConsensus,
+	}
+	sigs := make([][]byte, len(v.privs))
+	for i := range sigs {
+		sigs[i] = v.privs[i].SignHashable(testNetwork, header)
+	}
+	witness, _, err := n3.BuildWitness(v.pubs, sigs)
+	require.NoError(t, err)
+	header.Script = witness
+	return header
+}
+
+func putN3Header(t *testing.T, st *store.Store, header *block.Header) {
+	w := io.NewBufBinWriter()
+	header.EncodeBinary(w.BinWriter)
+	require.NoError(t, w.Err)
+	require.NoError(t, st.Put(uint64(header.Index), []store.Header{{Hash: header.Hash().BytesBE(), Data: w.Bytes()}}))
+}
+
+type eventReader struct {
+	scanner *bufio.Scanner
+}
+
+func openStream(t *testing.T, url string, lastID string) *eventReader {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	require.NoError(t, err)
+	if lastID != "" {
+		req.Header.Set("Last-Event-ID", lastID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { resp.Body.Close() })
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	return &eventReader{scanner: bufio.NewScanner(resp.Body)}
+}
+
+// next returns the id and the data of the next event.
+func (r *eventReader) next(t *testing.T) (string, Event) {
+	var (
+		id, typ string
+		e       Event
+	)
+	for r.scanner.Scan() {
+		line := r.scanner.Text()
+		switch {
+		case line == "":
+			require.Equal(t, typ, e.Type)
+			return id, e
+		case strings.HasPrefix(line, "id: "):
+			id = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "event: "):
+			typ = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &e))
+		}
+	}
+	require.NoError(t, r.scanner.Err())
+	t.Fatal("stream is closed")
+	return "", Event{}
+}
+
+func TestStream(t *testing.T) {
+	v, nextV := newTestValidators(t), newTestValidators(t)
+	trusted := &block.Header{Timestamp: 1628062127819, NextConsensus: v.hash(t)}
+	st := store.NewMemory()
+	client, err := syncer.ResumeN3(st, trusted, testNetwork)
+	require.NoError(t, err)
+	s := NewServer(&N3Backend{Client: client, Store: st}, nil)
+	var equivocations []string
+	s.OnEquivocation(func(chain string, e Event) {
+		equivocations = append(equivocations, chain)
+	})
+	server := httptest.NewServer(s)
+	// Streams are closed by cleanups registered later
+	t.Cleanup(server.Close)
+
+	// Validator set change at 1
+	h1 := v.child(t, trusted, nextV.hash(t), trusted.Timestamp+15000)
+	require.NoError(t, client.Update(h1))
+	putN3Header(t, st, h1)
+
+	stream := openStream(t, server.URL+"/v1/n3/stream?from=0", "")
+	id, e := stream.next(t)
+	require.Equal(t, "0", id)
+	require.Equal(t, EventHeader, e.Type)
+	require.Equal(t, "0x"+trusted.Hash().StringLE(), e.Hash)
+	require.Equal(t, "0x"+v.hash(t).StringLE(), e.Commitment)
+	id, e = stream.next(t)
+	require.Equal(t, "", id)
+	require.Equal(t, EventValidators, e.Type)
+	require.Equal(t, uint64(1), e.Height)
+	require.Equal(t, "0x"+v.hash(t).StringLE(), e.Previous)
+	require.Equal(t, "0x"+nextV.hash(t).StringLE(), e.Commitment)
+	require.Equal(t, n3.DefaultLimits.ValidatorsCount, len(e.Validators))
+	id, e = stream.next(t)
+	require.Equal(t, "1", id)
+	require.Equal(t, &Scheme{Name: "multisig", M: 5, N: n3.DefaultLimits.ValidatorsCount}, e.Scheme)
+
+	// Pushed once stored
+	h2 := nextV.child(t, h1, nextV.hash(t), h1.Timestamp+15000)
+	require.NoError(t, client.Update(h2))
+	putN3Header(t, st, h2)
+	s.Notify()
+	id, e = stream.next(t)
+	require.Equal(t, "2", id)
+	require.Equal(t, "0x"+h2.Hash().StringLE(), e.Hash)
+
+	// Equivocation at 1
+	h1b := v.child(t, trusted, nextV.hash(t), trusted.Timestamp+14000)
+	parent, err := trusted.MarshalJSON()
+	require.NoError(t, err)
+	header, err := h1b.MarshalJSON()
+	require.NoError(t, err)
+	var res VerifyResponse
+	require.Equal(t, http.StatusOK, post(t, server.URL+"/v1/n3/verify", "application/json", verifyBody(t, parent, header), &res))
+	require.Equal(t, VerifyResponse{Valid: true, Conflicting: "0x" + h1.Hash().StringLE()}, res)
+	_, e = stream.next(t)
+	require.Equal(t, Event{
+		Type:        EventEquivocation,
+		Height:      1,
+		Hash:        "0x" + h1b.Hash().StringLE(),
+		Conflicting: "0x" + h1.Hash().StringLE(),
+	}, e)
+	require.Equal(t, []string{"n3"}, equivocations)
+
+	// A child of a forged parent is valid but not an equivocation
+	forger := newTestValidators(t)
+	forged := &block.Header{Timestamp: trusted.Timestamp, NextConsensus: forger.hash(t)}
+	parent, err = forged.MarshalJSON()
+	require.NoError(t, err)
+	header, err = forger.child(t, forged, nextV.hash(t), forged.Timestamp+15000).MarshalJSON()
+	require.NoError(t, err)
+	res = VerifyResponse{}
+	require.Equal(t, http.StatusOK, post(t, server.URL+"/v1/n3/verify", "application/json", verifyBody(t, parent, header), &res))
+	require.Equal(t, VerifyResponse{Valid: true}, res)
+	require.Equal(t, []string{"n3"}, equivocations)
+
+	// Resume after the last received header
+	id, e = openStream(t, server.URL+"/v1/n3/stream", "1").next(t)
+	require.Equal(t, "2", id)
+	require.Equal(t, EventHeader, e.Type)
+
+	var errRes ErrorResponse
+	require.Equal(t, http.StatusBadRequest, get(t, server.URL+"/v1/n3/stream?from=x", &errRes))
+	resp, err := http.Post(server.URL+"/v1/neox/stream", "", bytes.NewReader(nil))
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestStreamValidators(t *testing.T) {
+	v, nextV := newTestValidators(t), newTestValidators(t)
+	trusted := &block.Header{Timestamp: 1628062127819, NextConsensus: v.hash(t)}
+	// The trusted header isn't stored
+	st := store.NewMemory()
+	client := n3.NewLightClient(trusted, testNetwork)
+	s := NewServer(&N3Backend{Client: client, Store: st}, nil)
+	server := httptest.NewServer(s)
+	t.Cleanup(server.Close)
+
+	h1 := v.child(t, trusted, nextV.hash(t), trusted.Timestamp+15000)
+	require.NoError(t, client.Update(h1))
+	putN3Header(t, st, h1)
+	stream := openStream(t, server.URL+"/v1/n3/stream?from=1", "")
+	id, e := stream.next(t)
+	require.Equal(t, "", id)
+	require.Equal(t, EventValidators, e.Type)
+	require.Equal(t, uint64(1), e.Height)
+	require.Equal(t, "0x"+h1.Hash().StringLE(), e.Hash)
+	require.Equal(t, "0x"+v.hash(t).StringLE(), e.Previous)
+	require.Equal(t, "0x"+nextV.hash(t).StringLE(), e.Commitment)
+	require.Equal(t, n3.DefaultLimits.ValidatorsCount, len(e.Validators))
+	id, _ = stream.next(t)
+	require.Equal(t, "1", id)
+
+	// Header stored before the client reports the change
+	h2 := nextV.child(t, h1, v.hash(t), h1.Timestamp+15000)
+	putN3Header(t, st, h2)
+	s.Notify()
+	id, _ = stream.next(t)
+	require.Equal(t, "2", id)
+	require.NoError(t, client.Update(h2))
+	_, e = stream.next(t)
+	require.Equal(t, EventValidators, e.Type)
+	require.Equal(t, uint64(2), e.Height)
+	require.Equal(t, "0x"+h2.Hash().StringLE(), e.Hash)
+
+	// Each change is sent once
+	h3 := v.child(t, h2, v.hash(t), h2.Timestamp+15000)
+	require.NoError(t, client.Update(h3))
+	putN3Header(t, st, h3)
+	s.Notify()
+	id, e = stream.next(t)
+	require.Equal(t, "3", id)
+	require.Equal(t, EventHeader, e.Type)
+}
+
+func TestNeoXStream(t *testing.T) {
+	server, _, neoXRaws := newTestServer(t)
+	trusted := new(types.Header)
+	require.NoError(t, trusted.UnmarshalJSON(neoXRaws[0]))
+
+	id, e := openStream(t, server.URL+"/v1/neox/stream?from=2087998", "").next(t)
+	require.Equal(t, "2087998", id)
+	require.Equal(t, trusted.Hash().Hex(), e.Hash)
+	require.Equal(t, trusted.MixDigest.Hex(), e.Commitment)
+	require.NotNil(t, e.Scheme)
+
+	var errRes ErrorResponse
+	require.Equal(t, http.StatusNotFound, get(t, server.URL+"/v1/neox/stream?from=1", &errRes))
+	require.Equal(t, ReasonNotFound, errRes.Reason)
+}
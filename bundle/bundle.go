@@ -0,0 +1,232 @@
+// Package bundle implements a portable format for proofs anchored in headers
+// verified by the n3 and neox verifiers.
+package bundle
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/nspcc-dev/neo-go/pkg/core/block"
+	"github.com/nspcc-dev/neo-go/pkg/core/mpt"
+	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	n3 "github.com/txhsl/n3-dbft-verifier"
+	neox "github.com/txhsl/neox-dbft-verifier"
+)
+
+// Version is the current version of the bundle format.
+const Version byte = 1
+
+// Chain is the chain a bundle belongs to.
+type Chain byte
+
+const (
+	ChainN3   Chain = 0x01
+	ChainNeoX Chain = 0x02
+)
+
+// ProofKind is the kind of inclusion proof carried by a bundle.
+type ProofKind byte
+
+const (
+	// ProofNone proves the headers only.
+	ProofNone ProofKind = 0x00
+	// ProofN3Tx proves the transaction hash Key at Index under MerkleRoot, Nodes are
+	// the Merkle path.
+	ProofN3Tx ProofKind = 0x01
+	// ProofN3State proves the storage Key holds Value under PrevStateRoot, Nodes are
+	// the MPT proof. It requires a network with state root in header.
+	ProofN3State ProofKind = 0x02
+	// ProofNeoXTx proves the transaction binary Value at Index under TxHash, Nodes
+	// are the trie proof.
+	ProofNeoXTx ProofKind = 0x11
+	// ProofNeoXReceipt proves the receipt encoding Value at Index under ReceiptHash,
+	// Nodes are the trie proof.
+	ProofNeoXReceipt ProofKind = 0x12
+	// ProofNeoXStorage proves the slot Key[20:] of the contract Key[:20] holds Value
+	// under Root, AccountNodes and Nodes are the eth_getProof proofs.
+	ProofNeoXStorage ProofKind = 0x13
+)
+
+var (
+	// ErrUntrustedAnchor is returned for bundles anchored in unknown headers.
+	ErrUntrustedAnchor = errors.New("untrusted anchor")
+	// ErrInvalidProof is returned for inclusion proofs that don't hold.
+	ErrInvalidProof = errors.New("invalid proof")
+)
+
+// Proof is an inclusion proof against the last header of a bundle.
+type Proof struct {
+	Kind         ProofKind       `json:"kind"`
+	Index        uint64          `json:"index"`
+	Key          hexutil.Bytes   `json:"key"`
+	Value        hexutil.Bytes   `json:"value"`
+	Nodes        []hexutil.Bytes `json:"nodes"`
+	AccountNodes []hexutil.Bytes `json:"accountNodes"`
+}
+
+// ProofBundle is a chain of headers on top of a trusted anchor followed by an
+// inclusion proof. N3 headers are in the neo-go binary format and hashes are in
+// big-endian order, Neo X headers are RLP encoded. Both the binary (RLP) and the
+// JSON encodings are deterministic.
+type ProofBundle struct {
+	Version byte            `json:"version"`
+	Chain   Chain           `json:"chain"`
+	Anchor  common.Hash     `json:"anchor"`
+	Headers []hexutil.Bytes `json:"headers"`
+	Proof   Proof           `json:"proof"`
+}
+
+func (b *ProofBundle) MarshalBinary() ([]byte, error) {
+	return rlp.EncodeToBytes(b)
+}
+
+func (b *ProofBundle) UnmarshalBinary(data []byte) error {
+	if err := rlp.DecodeBytes(data, b); err != nil {
+		return err
+	}
+	if b.Version != Version {
+		return fmt.Errorf("unsupported bundle version %d", b.Version)
+	}
+	return nil
+}
+
+// Verify checks the headers against the anchor taken from store and the proof
+// against the last header.
+func (b *ProofBundle) Verify(store *TrustStore) error {
+	if store == nil {
+		return errors.New("no trust store")
+	}
+	if b.Version != Version {
+		return fmt.Errorf("unsupported bundle version %d", b.Version)
+	}
+	switch b.Chain {
+	case ChainN3:
+		return b.verifyN3(store)
+	case ChainNeoX:
+		return b.verifyNeoX(store)
+	default:
+		return fmt.Errorf("unknown chain %d", b.Chain)
+	}
+}
+
+func (b *ProofBundle) verifyN3(store *TrustStore) error {
+	parent, ok := store.N3Header(util.Uint256(b.Anchor).Reverse())
+	if !ok {
+		return ErrUntrustedAnchor
+	}
+	for i, data := range b.Headers {
+		current := &block.Header{StateRootEnabled: store.N3StateRootInHeader}
+		r := io.NewBinReaderFromBuf(data)
+		current.DecodeBinary(r)
+		if r.Err == nil && r.Len() != 0 {
+			r.Err = errors.New("trailing data")
+		}
+		if r.Err != nil {
+			return fmt.Errorf("header %d: %w", i, r.Err)
+		}
+		if err := n3.CheckUpdateHeader(parent, current, store.N3Network); err != nil {
+			return fmt.Errorf("header %d: %w", current.Index, err)
+		}
+		parent = current
+	}
+	p := &b.Proof
+	switch p.Kind {
+	case ProofNone:
+		return nil
+	case ProofN3Tx:
+		if len(p.Key) != util.Uint256Size {
+			return errors.New("malformed tx hash")
+		}
+		if p.Index > math.MaxUint32 {
+			return errors.New("tx index out of range")
+		}
+		path := make([]util.Uint256, len(p.Nodes))
+		for i, node := range p.Nodes {
+			if len(node) != util.Uint256Size {
+				return errors.New("malformed merkle path")
+			}
+			path[i] = util.Uint256(node).Reverse()
+		}
+		if !n3.VerifyMerkleProof(parent.MerkleRoot, util.Uint256(p.Key).Reverse(), uint32(p.Index), path) {
+			return fmt.Errorf("%w: merkle path mismatch", ErrInvalidProof)
+		}
+		return nil
+	case ProofN3State:
+		if !store.N3StateRootInHeader {
+			return errors.New("state root is not in header")
+		}
+		nodes := make([][]byte, len(p.Nodes))
+		for i := range p.Nodes {
+			nodes[i] = p.Nodes[i]
+		}
+		value, ok := mpt.VerifyProof(parent.PrevStateRoot, p.Key, nodes)
+		if !ok || !bytes.Equal(value, p.Value) {
+			return fmt.Errorf("%w: state proof mismatch", ErrInvalidProof)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unexpected proof kind %d", p.Kind)
+	}
+}
+
+func (b *ProofBundle) verifyNeoX(store *TrustStore) error {
+	parent, ok := store.NeoXHeader(b.Anchor)
+	if !ok {
+		return ErrUntrustedAnchor
+	}
+	for i, data := range b.Headers {
+		current := new(types.Header)
+		if err := rlp.DecodeBytes(data, current); err != nil {
+			return fmt.Errorf("header %d: %w", i, err)
+		}
+		if err := neox.CheckUpdateHeader(store.neoXConfig(), parent, current); err != nil {
+			return fmt.Errorf("header %d: %w", current.Number, err)
+		}
+		parent = current
+	}
+	p := &b.Proof
+	nodes := make([][]byte, len(p.Nodes))
+	for i := range p.Nodes {
+		nodes[i] = p.Nodes[i]
+	}
+	var (
+		value []byte
+		err   error
+	)
+	switch p.Kind {
+	case ProofNone:
+		return nil
+	case ProofNeoXTx:
+		value, err = neox.VerifyTxProof(parent, p.Index, nodes)
+	case ProofNeoXReceipt:
+		value, err = neox.VerifyReceiptProof(parent, p.Index, nodes)
+	case ProofNeoXStorage:
+		if len(p.Key) != common.AddressLength+common.HashLength {
+			return errors.New("malformed storage key")
+		}
+		accountNodes := make([][]byte, len(p.AccountNodes))
+		for i := range p.AccountNodes {
+			accountNodes[i] = p.AccountNodes[i]
+		}
+		var slot common.Hash
+		slot, err = neox.VerifyStorageProof(parent, common.BytesToAddress(p.Key[:common.AddressLength]),
+			common.BytesToHash(p.Key[common.AddressLength:]), accountNodes, nodes)
+		value = slot[:]
+	default:
+		return fmt.Errorf("unexpected proof kind %d", p.Kind)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidProof, err)
+	}
+	if !bytes.Equal(value, p.Value) {
+		return fmt.Errorf("%w: unexpected proven value", ErrInvalidProof)
+	}
+	return nil
+}
@@ -0,0 +1,228 @@
+package bundle
+
+import (
+	"encoding/json"
+	"os"
+	"slices"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/nspcc-dev/neo-go/pkg/core/block"
+	"github.com/nspcc-dev/neo-go/pkg/core/mpt"
+	"github.com/nspcc-dev/neo-go/pkg/core/storage"
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/hash"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/stretchr/testify/require"
+	n3 "github.com/txhsl/n3-dbft-verifier"
+	neox "github.com/txhsl/neox-dbft-verifier"
+)
+
+func loadN3Headers(t *testing.T) []*block.Header {
+	data, err := os.ReadFile("testdata/n3_headers.json")
+	require.NoError(t, err)
+	var raws []json.RawMessage
+	require.NoError(t, json.Unmarshal(data, &raws))
+	headers := make([]*block.Header, len(raws))
+	for i, raw := range raws {
+		headers[i] = new(block.Header)
+		require.NoError(t, headers[i].UnmarshalJSON(raw))
+	}
+	return headers
+}
+
+func loadNeoXHeaders(t *testing.T) []*types.Header {
+	data, err := os.ReadFile("testdata/neox_headers.json")
+	require.NoError(t, err)
+	var headers []*types.Header
+	require.NoError(t, json.Unmarshal(data, &headers))
+	return headers
+}
+
+func TestN3Bundle(t *testing.T) {
+	headers := loadN3Headers(t)
+	store := NewTrustStore(860833102, neox.DefaultChainConfig)
+	store.AddN3Header(headers[0])
+	w := io.NewBufBinWriter()
+	headers[1].EncodeBinary(w.BinWriter)
+	require.NoError(t, w.Err)
+	b := &ProofBundle{
+		Version: Version,
+		Chain:   ChainN3,
+		Anchor:  common.Hash(headers[0].Hash().Reverse()),
+		Headers: []hexutil.Bytes{w.Bytes()},
+	}
+	require.NoError(t, b.Verify(store))
+	// Trailing data
+	b.Headers[0] = append(b.Headers[0], 0)
+	require.Error(t, b.Verify(store))
+	b.Headers[0] = b.Headers[0][:len(b.Headers[0])-1]
+
+	// Empty block has zero MerkleRoot
+	b.Proof = Proof{Kind: ProofN3Tx, Key: make([]byte, 32)}
+	require.NoError(t, b.Verify(store))
+	b.Proof.Key[0] = 1
+	require.ErrorIs(t, b.Verify(store), ErrInvalidProof)
+	b.Proof = Proof{Kind: ProofN3State}
+	require.Error(t, b.Verify(store))
+
+	// Untrusted anchor
+	b.Proof = Proof{}
+	b.Anchor = common.Hash(headers[1].Hash().Reverse())
+	require.ErrorIs(t, b.Verify(store), ErrUntrustedAnchor)
+}
+
+func TestNeoXBundle(t *testing.T) {
+	headers := loadNeoXHeaders(t)
+	store := NewTrustStore(860833102, neox.DefaultChainConfig)
+	store.AddNeoXHeader(headers[0])
+	b := &ProofBundle{
+		Version: Version,
+		Chain:   ChainNeoX,
+		Anchor:  headers[0].Hash(),
+	}
+	for _, header := range headers[1:] {
+		data, err := rlp.EncodeToBytes(header)
+		require.NoError(t, err)
+		b.Headers = append(b.Headers, data)
+	}
+	require.NoError(t, b.Verify(store))
+
+	// Binary and JSON roundtrips
+	data, err := b.MarshalBinary()
+	require.NoError(t, err)
+	decoded := new(ProofBundle)
+	require.NoError(t, decoded.UnmarshalBinary(data))
+	require.NoError(t, decoded.Verify(store))
+	again, err := decoded.MarshalBinary()
+	require.NoError(t, err)
+	require.Equal(t, data, again)
+	data, err = json.Marshal(b)
+	require.NoError(t, err)
+	decoded = new(ProofBundle)
+	require.NoError(t, json.Unmarshal(data, decoded))
+	require.NoError(t, decoded.Verify(store))
+
+	// Nil config is the default one
+	nilConfig := NewTrustStore(860833102, nil)
+	nilConfig.AddNeoXHeader(headers[0])
+	require.NoError(t, b.Verify(nilConfig))
+
+	// Empty block has no transactions
+	b.Proof = Proof{Kind: ProofNeoXTx}
+	require.ErrorIs(t, b.Verify(store), ErrInvalidProof)
+
+	// Broken chain
+	b.Proof = Proof{}
+	b.Headers = b.Headers[1:]
+	require.ErrorIs(t, b.Verify(store), neox.ErrParentHash)
+
+	// Unknown version
+	b.Version = Version + 1
+	require.Error(t, b.Verify(store))
+	data, err = b.MarshalBinary()
+	require.NoError(t, err)
+	require.Error(t, new(ProofBundle).UnmarshalBinary(data))
+}
+
+// newN3Child returns a child of parent with the state root in header signed by
+// validators with the script hash of parent NextConsensus.
+func newN3Child(t *testing.T, privs []*keys.PrivateKey, parent *block.Header, merkleRoot, stateRoot util.Uint256) *block.Header {
+	pubs := make(keys.PublicKeys, len(privs))
+	for i := range privs {
+		pubs[i] = privs[i].PublicKey()
+	}
+	header := &block.Header{
+		StateRootEnabled: true,
+		PrevHash:         parent.Hash(),
+		MerkleRoot:       merkleRoot,
+		Timestamp:        parent.Timestamp + 15000,
+		Index:            parent.Index + 1,
+		NextConsensus:    parent.NextConsensus,
+		PrevStateRoot:    stateRoot,
+	}
+	sigs := make([][]byte, len(privs))
+	for i := range privs {
+		sigs[i] = privs[i].SignHashable(860833102, header)
+	}
+	witness, _, err := n3.BuildWitness(pubs, sigs)
+	require.NoError(t, err)
+	header.Script = witness
+	return header
+}
+
+func TestN3BundleProofs(t *testing.T) {
+	privs := make([]*keys.PrivateKey, 7)
+	pubs := make(keys.PublicKeys, len(privs))
+	sigs := make([][]byte, len(privs))
+	for i := range privs {
+		priv, err := keys.NewPrivateKey()
+		require.NoError(t, err)
+		privs[i], pubs[i], sigs[i] = priv, priv.PublicKey(), make([]byte, n3.SignatureLen)
+	}
+	_, nextConsensus, err := n3.BuildWitness(pubs, sigs)
+	require.NoError(t, err)
+
+	// Transactions and storage committed by the header
+	var hashes []util.Uint256
+	for i := range 3 {
+		hashes = append(hashes, transaction.New([]byte{byte(i)}, 0).Hash())
+	}
+	trie := mpt.NewTrie(nil, mpt.ModeAll, storage.NewMemCachedStore(storage.NewMemoryStore()))
+	require.NoError(t, trie.Put([]byte{0x01, 0x02}, []byte("value")))
+	require.NoError(t, trie.Put([]byte{0x01, 0x03}, []byte("other")))
+	trie.Flush(0)
+	stateProof, err := trie.GetProof([]byte{0x01, 0x02})
+	require.NoError(t, err)
+
+	anchor := &block.Header{StateRootEnabled: true, Timestamp: 1628062127819, Index: 100, NextConsensus: nextConsensus}
+	header := newN3Child(t, privs, anchor, hash.CalcMerkleRoot(slices.Clone(hashes)), trie.StateRoot())
+	store := NewTrustStore(860833102, neox.DefaultChainConfig)
+	store.N3StateRootInHeader = true
+	store.AddN3Header(anchor)
+	w := io.NewBufBinWriter()
+	header.EncodeBinary(w.BinWriter)
+	require.NoError(t, w.Err)
+	b := &ProofBundle{
+		Version: Version,
+		Chain:   ChainN3,
+		Anchor:  common.Hash(anchor.Hash().Reverse()),
+		Headers: []hexutil.Bytes{w.Bytes()},
+	}
+
+	// Transaction proof
+	path, err := n3.MerkleProof(hashes, 1)
+	require.NoError(t, err)
+	b.Proof = Proof{Kind: ProofN3Tx, Index: 1, Key: hashes[1].BytesLE()}
+	for _, node := range path {
+		b.Proof.Nodes = append(b.Proof.Nodes, node.BytesLE())
+	}
+	require.NoError(t, b.Verify(store))
+	b.Proof.Index = 2
+	require.ErrorIs(t, b.Verify(store), ErrInvalidProof)
+	// Index that would truncate to 1
+	b.Proof.Index = 1<<32 + 1
+	require.Error(t, b.Verify(store))
+	require.NotErrorIs(t, b.Verify(store), ErrInvalidProof)
+	b.Proof.Index = 1
+	b.Proof.Key = hashes[2].BytesLE()
+	require.ErrorIs(t, b.Verify(store), ErrInvalidProof)
+
+	// Storage proof
+	b.Proof = Proof{Kind: ProofN3State, Key: []byte{0x01, 0x02}, Value: []byte("value")}
+	for _, node := range stateProof {
+		b.Proof.Nodes = append(b.Proof.Nodes, node)
+	}
+	require.NoError(t, b.Verify(store))
+	b.Proof.Value = []byte("other")
+	require.ErrorIs(t, b.Verify(store), ErrInvalidProof)
+	b.Proof.Key, b.Proof.Value = []byte{0x01, 0x03}, []byte("other")
+	require.ErrorIs(t, b.Verify(store), ErrInvalidProof)
+
+	require.Error(t, b.Verify(nil))
+}
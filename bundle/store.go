@@ -0,0 +1,66 @@
+package bundle
+
+import (
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/nspcc-dev/neo-go/pkg/core/block"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	neox "github.com/txhsl/neox-dbft-verifier"
+)
+
+// TrustStore keeps the headers bundles may be anchored in, together with the
+// network parameters needed to verify on top of them. A nil NeoXConfig is
+// neox.DefaultChainConfig.
+type TrustStore struct {
+	N3Network           uint32
+	N3StateRootInHeader bool
+	NeoXConfig          *neox.ChainConfig
+
+	lock sync.RWMutex
+	n3   map[util.Uint256]*block.Header
+	neoX map[common.Hash]*types.Header
+}
+
+func NewTrustStore(n3Network uint32, neoXConfig *neox.ChainConfig) *TrustStore {
+	return &TrustStore{
+		N3Network:  n3Network,
+		NeoXConfig: neoXConfig,
+		n3:         make(map[util.Uint256]*block.Header),
+		neoX:       make(map[common.Hash]*types.Header),
+	}
+}
+
+func (s *TrustStore) neoXConfig() *neox.ChainConfig {
+	if s.NeoXConfig == nil {
+		return neox.DefaultChainConfig
+	}
+	return s.NeoXConfig
+}
+
+func (s *TrustStore) AddN3Header(header *block.Header) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.n3[header.Hash()] = header
+}
+
+func (s *TrustStore) AddNeoXHeader(header *types.Header) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.neoX[header.Hash()] = header
+}
+
+func (s *TrustStore) N3Header(hash util.Uint256) (*block.Header, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	header, ok := s.n3[hash]
+	return header, ok
+}
+
+func (s *TrustStore) NeoXHeader(hash common.Hash) (*types.Header, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	header, ok := s.neoX[hash]
+	return header, ok
+}
@@ -0,0 +1,138 @@
+// Command dbft-analytics reports validator statistics over a height range of a header
+// store written by dbft-sync.
+//
+// Usage:
+//
+//	dbft-analytics participation <n3|neox> -db <path> [-from <height>] [-to <height>] [-format json|csv]
+//	dbft-analytics views <n3|neox> -db <path> [-from <height>] [-to <height>] [-threshold <rate>] [-format json|csv]
+//
+// The participation report covers signatures of validators, the views report covers
+// view changes and flags validators failing as primary more often than the threshold.
+// The range defaults to the whole store. Reports are written to stdout. Neo X
+// participation uses the fork schedule the store was synced with, or the one of the
+// dbft-verify schedule flags.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/txhsl/dbft-verifier/analytics"
+	"github.com/txhsl/dbft-verifier/cmd/internal/chainflags"
+	"github.com/txhsl/dbft-verifier/store"
+	"github.com/txhsl/dbft-verifier/syncer"
+	neox "github.com/txhsl/neox-dbft-verifier"
+)
+
+const usage = `usage: dbft-analytics <participation|views> <n3|neox> -db <path> [-from <height>] [-to <height>] [-format json|csv]`
+
+// report is a report that can be written as JSON or CSV.
+type report interface {
+	WriteCSV(w io.Writer) error
+}
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdout, stderr io.Writer) error {
+	if len(args) < 2 || (args[0] != "participation" && args[0] != "views") {
+		return errors.New(usage)
+	}
+	command, chain := args[0], args[1]
+	fs := flag.NewFlagSet(chain, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	db := fs.String("db", "", "header store directory")
+	from := fs.Uint64("from", 0, "first height, the lowest stored by default")
+	to := fs.Uint64("to", 0, "last height, the head by default")
+	format := fs.String("format", "json", "report format, json or csv")
+	network := fs.Uint("network", 860833102, "N3 network magic, the one the store was synced with by default")
+	validators := fs.Int("validators", neox.ValidatorsCount, "Neo X validators count of headers that don't reveal the validator set")
+	threshold := fs.Float64("threshold", analytics.DefaultFailureThreshold, "primary failure rate to flag validators above")
+	chainConfig := chainflags.NeoX(fs)
+	if err := fs.Parse(args[2:]); err != nil {
+		return err
+	}
+	if *db == "" || (*format != "json" && *format != "csv") {
+		return errors.New(usage)
+	}
+	set := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+
+	st, err := store.Open(*db)
+	if err != nil {
+		return err
+	}
+	defer st.Close()
+	if !set["from"] {
+		if *from, err = st.Tail(); err != nil {
+			return err
+		}
+	}
+	if !set["to"] {
+		if *to, _, err = st.Head(); err != nil {
+			return err
+		}
+	}
+	var r report
+	switch {
+	case command == "participation" && chain == "n3":
+		var magic uint32
+		if magic, err = n3Network(set["network"], uint32(*network), st); err == nil {
+			r, err = analytics.N3Participation(st, magic, *from, *to)
+		}
+	case command == "participation" && chain == "neox":
+		var config *neox.ChainConfig
+		if config, err = neoXConfig(fs, chainConfig, st); err == nil {
+			r, err = analytics.NeoXParticipation(st, config, *from, *to)
+		}
+	case command == "views" && chain == "n3":
+		r, err = analytics.N3Views(st, *from, *to, *threshold)
+	case command == "views" && chain == "neox":
+		r, err = analytics.NeoXViews(st, *from, *to, *validators, *threshold)
+	default:
+		return errors.New(usage)
+	}
+	if err != nil {
+		return err
+	}
+	if *format == "csv" {
+		return r.WriteCSV(stdout)
+	}
+	enc := json.NewEncoder(stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// n3Network returns network if the flag is set, the network st was synced with
+// otherwise, or network for stores without one.
+func n3Network(set bool, network uint32, st *store.Store) (uint32, error) {
+	if set {
+		return network, nil
+	}
+	stored, err := syncer.LoadN3Network(st)
+	if errors.Is(err, store.ErrNotFound) {
+		return network, nil
+	}
+	return stored, err
+}
+
+// neoXConfig returns the config of the schedule flags if any is set, the config st was
+// synced with otherwise, or neox.DefaultChainConfig for stores without one.
+func neoXConfig(fs *flag.FlagSet, chainConfig func() (*neox.ChainConfig, error), st *store.Store) (*neox.ChainConfig, error) {
+	if chainflags.NeoXSet(fs) {
+		return chainConfig()
+	}
+	config, err := syncer.LoadNeoXConfig(st)
+	if errors.Is(err, store.ErrNotFound) {
+		return neox.DefaultChainConfig, nil
+	}
+	return config, err
+}
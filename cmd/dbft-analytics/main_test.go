@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/nspcc-dev/neo-go/pkg/core/block"
+	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/stretchr/testify/require"
+	"github.com/txhsl/dbft-verifier/analytics"
+	"github.com/txhsl/dbft-verifier/store"
+	"github.com/txhsl/dbft-verifier/syncer"
+	neox "github.com/txhsl/neox-dbft-verifier"
+)
+
+func TestRun(t *testing.T) {
+	data, err := os.ReadFile("../../bundle/testdata/n3_headers.json")
+	require.NoError(t, err)
+	var raws []json.RawMessage
+	require.NoError(t, json.Unmarshal(data, &raws))
+	db := filepath.Join(t.TempDir(), "db")
+	st, err := store.Open(db)
+	require.NoError(t, err)
+	for _, raw := range raws {
+		header := new(block.Header)
+		require.NoError(t, header.UnmarshalJSON(raw))
+		w := io.NewBufBinWriter()
+		header.EncodeBinary(w.BinWriter)
+		require.NoError(t, w.Err)
+		require.NoError(t, st.Put(uint64(header.Index), []store.Header{{Hash: header.Hash().BytesBE(), Data: w.Bytes()}}))
+	}
+	require.NoError(t, st.Close())
+
+	var stdout bytes.Buffer
+	require.NoError(t, run([]string{"participation", "n3", "-db", db}, &stdout, &bytes.Buffer{}))
+	var report analytics.ParticipationReport
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &report))
+	require.Equal(t, uint64(9999), report.From)
+	require.Equal(t, uint64(10000), report.To)
+	require.Equal(t, 2, report.Blocks)
+
+	stdout.Reset()
+	require.NoError(t, run([]string{"participation", "n3", "-db", db, "-from", "10000", "-format", "csv"}, &stdout, &bytes.Buffer{}))
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	require.Equal(t, 8, len(lines))
+	require.Equal(t, true, strings.HasPrefix(lines[0], "validator,"))
+
+	require.Error(t, run([]string{"participation", "n3", "-db", db, "-to", "10001"}, &stdout, &bytes.Buffer{}))
+	require.Error(t, run([]string{"participation", "n3", "-db", db, "-format", "xml"}, &stdout, &bytes.Buffer{}))
+	require.Error(t, run([]string{"participation", "eth", "-db", db}, &stdout, &bytes.Buffer{}))
+
+	// Stored network
+	st, err = store.Open(db)
+	require.NoError(t, err)
+	require.NoError(t, syncer.SaveN3Network(st, 894710606))
+	require.NoError(t, st.Close())
+	require.Error(t, run([]string{"participation", "n3", "-db", db}, &stdout, &bytes.Buffer{}))
+	stdout.Reset()
+	require.NoError(t, run([]string{"participation", "n3", "-db", db, "-network", "860833102"}, &stdout, &bytes.Buffer{}))
+	require.Error(t, run([]string{"views"}, &stdout, &bytes.Buffer{}))
+	require.Error(t, run([]string{"stats", "n3", "-db", db}, &stdout, &bytes.Buffer{}))
+
+	stdout.Reset()
+	require.NoError(t, run([]string{"views", "n3", "-db", db, "-threshold", "0.5"}, &stdout, &bytes.Buffer{}))
+	var views analytics.ViewReport
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &views))
+	require.Equal(t, 2, views.Blocks)
+	require.Equal(t, 0.5, views.Threshold)
+	require.Equal(t, map[int]int{0: 2}, views.Views)
+
+	stdout.Reset()
+	require.NoError(t, run([]string{"views", "n3", "-db", db, "-format", "csv"}, &stdout, &bytes.Buffer{}))
+	lines = strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	require.Equal(t, 3, len(lines))
+	require.Equal(t, true, strings.HasPrefix(lines[0], "validator,proposed,"))
+}
+
+func TestRunNeoXConfig(t *testing.T) {
+	data, err := os.ReadFile("../../bundle/testdata/neox_headers.json")
+	require.NoError(t, err)
+	var headers []*types.Header
+	require.NoError(t, json.Unmarshal(data, &headers))
+	db := filepath.Join(t.TempDir(), "db")
+	st, err := store.Open(db)
+	require.NoError(t, err)
+	for _, header := range headers {
+		data, err := rlp.EncodeToBytes(header)
+		require.NoError(t, err)
+		require.NoError(t, st.Put(header.Number.Uint64(), []store.Header{{Hash: header.Hash().Bytes(), Data: data}}))
+	}
+	// Synced without Shanghai
+	require.NoError(t, syncer.SaveNeoXConfig(st, &neox.ChainConfig{}))
+	require.NoError(t, st.Close())
+
+	var stdout bytes.Buffer
+	require.Error(t, run([]string{"participation", "neox", "-db", db}, &stdout, &bytes.Buffer{}))
+	require.NoError(t, run([]string{"participation", "neox", "-db", db, "-shanghai-time", "0"}, &stdout, &bytes.Buffer{}))
+	var report analytics.ParticipationReport
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &report))
+	// Threshold scheme headers hide the signers
+	require.Equal(t, len(headers), report.Blocks+report.Skipped)
+}
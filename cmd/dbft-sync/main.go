@@ -0,0 +1,234 @@
+// Command dbft-sync follows an N3 or Neo X network from a trusted header, verifying
+// and storing headers. It resumes from the stored head after a restart.
+//
+// Usage:
+//
+//	dbft-sync n3 -rpc <url> -db <path> [-trusted <file>] [-http <addr>] [flags]
+//	dbft-sync neox -rpc <url> -db <path> [-trusted <file>] [-http <addr>] [flags]
+//
+// The trusted header is a JSON file in the dbft-verify input format, it's only needed
+// for an empty store. With -http the verification API of package api and Prometheus
+// metrics on /metrics are served on addr while syncing.
+//
+// The Neo X fork schedule flags are the ones of dbft-verify. The schedule is stored with
+// the headers and used when resuming without them, a store synced with another one is
+// rejected.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/nspcc-dev/neo-go/pkg/core/block"
+	"github.com/txhsl/dbft-verifier/api"
+	"github.com/txhsl/dbft-verifier/cmd/internal/chainflags"
+	"github.com/txhsl/dbft-verifier/metrics"
+	"github.com/txhsl/dbft-verifier/store"
+	"github.com/txhsl/dbft-verifier/syncer"
+	n3 "github.com/txhsl/n3-dbft-verifier"
+	neox "github.com/txhsl/neox-dbft-verifier"
+)
+
+const usage = `usage: dbft-sync <n3|neox> -rpc <url> -db <path> [-trusted <file>] [-http <addr>] [flags]`
+
+type options struct {
+	rpc     string
+	db      string
+	trusted string
+	http    string
+	cfg     syncer.Config
+	logger  *log.Logger
+	metrics *metrics.Metrics
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := run(ctx, os.Args[1:], os.Stderr); err != nil && !errors.Is(err, context.Canceled) {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, args []string, stderr io.Writer) error {
+	if len(args) < 1 {
+		return errors.New(usage)
+	}
+	fs := flag.NewFlagSet(args[0], flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	opts := new(options)
+	fs.StringVar(&opts.rpc, "rpc", "", "JSON-RPC endpoint")
+	fs.StringVar(&opts.db, "db", "", "header store directory")
+	fs.StringVar(&opts.trusted, "trusted", "", "file with the trusted header, used for an empty store")
+	fs.StringVar(&opts.http, "http", "", "address to serve the verification API on")
+	fs.IntVar(&opts.cfg.BatchSize, "batch", 100, "headers per request")
+	fs.DurationVar(&opts.cfg.PollInterval, "poll", time.Second, "tip polling interval")
+	fs.DurationVar(&opts.cfg.ReportInterval, "report", 10*time.Second, "progress reporting interval")
+	logger := log.New(stderr, "", log.LstdFlags)
+	opts.logger = logger
+	opts.metrics = metrics.New()
+	opts.cfg.Report = func(p syncer.Progress) {
+		logger.Printf("height=%d tip=%d lag=%d rate=%.1f/s", p.Height, p.Tip, p.Lag(), p.Rate)
+	}
+	opts.cfg.OnFetchError = func(err error) {
+		logger.Printf("fetch: %v", err)
+	}
+	switch args[0] {
+	case "n3":
+		network := fs.Uint("network", 860833102, "network magic")
+		if err := parse(fs, args[1:], opts); err != nil {
+			return err
+		}
+		return syncN3(ctx, opts, uint32(*network))
+	case "neox":
+		chainConfig := chainflags.NeoX(fs)
+		if err := parse(fs, args[1:], opts); err != nil {
+			return err
+		}
+		// The config the store was synced with is used without flags
+		var config *neox.ChainConfig
+		if chainflags.NeoXSet(fs) {
+			var err error
+			if config, err = chainConfig(); err != nil {
+				return err
+			}
+		}
+		return syncNeoX(ctx, opts, config)
+	default:
+		return errors.New(usage)
+	}
+}
+
+func parse(fs *flag.FlagSet, args []string, opts *options) error {
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if opts.rpc == "" || opts.db == "" {
+		return errors.New(usage)
+	}
+	return nil
+}
+
+func syncN3(ctx context.Context, opts *options, network uint32) error {
+	var trusted *block.Header
+	if opts.trusted != "" {
+		src, err := n3.NewFileSource(opts.trusted)
+		if err != nil {
+			return err
+		}
+		height, _ := src.Height(ctx)
+		headers, err := src.Headers(ctx, height, 1)
+		if err != nil {
+			return err
+		}
+		trusted = headers[0]
+	}
+	st, err := store.Open(opts.db)
+	if err != nil {
+		return err
+	}
+	defer st.Close()
+	client, err := syncer.ResumeN3(st, trusted, network)
+	if err != nil {
+		return err
+	}
+	if err := serveAPI(ctx, opts, api.NewServer(&api.N3Backend{Client: client, Store: st}, nil)); err != nil {
+		return err
+	}
+	chain := metrics.InstrumentN3(opts.metrics, &syncer.N3Chain{Client: client, Source: n3.NewRPCSource(opts.rpc)})
+	return syncer.New[*block.Header](chain, st, opts.cfg).Run(ctx)
+}
+
+// syncNeoX syncs with config, or the config the store was synced with if it's nil.
+// The config is stored, a store synced with another one is rejected.
+func syncNeoX(ctx context.Context, opts *options, config *neox.ChainConfig) error {
+	var trusted *types.Header
+	if opts.trusted != "" {
+		src, err := neox.NewFileSource(opts.trusted)
+		if err != nil {
+			return err
+		}
+		height, _ := src.Height(ctx)
+		headers, err := src.Headers(ctx, height, 1)
+		if err != nil {
+			return err
+		}
+		trusted = headers[0]
+	}
+	source, err := neox.DialRPCSource(ctx, opts.rpc)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+	st, err := store.Open(opts.db)
+	if err != nil {
+		return err
+	}
+	defer st.Close()
+	if config == nil {
+		config, err = syncer.LoadNeoXConfig(st)
+		if errors.Is(err, store.ErrNotFound) {
+			config, err = neox.DefaultChainConfig, nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if err := syncer.SaveNeoXConfig(st, config); err != nil {
+		return err
+	}
+	client, err := syncer.ResumeNeoX(st, trusted, config)
+	if err != nil {
+		return err
+	}
+	if err := serveAPI(ctx, opts, api.NewServer(nil, &api.NeoXBackend{Client: client, Store: st})); err != nil {
+		return err
+	}
+	chain := metrics.InstrumentNeoX(opts.metrics, &syncer.NeoXChain{Client: client, Source: source})
+	return syncer.New[*types.Header](chain, st, opts.cfg).Run(ctx)
+}
+
+// serveAPI serves server and the metrics on the -http address until ctx is done, it's
+// a no-op without it. Streams are notified of stored headers.
+func serveAPI(ctx context.Context, opts *options, server *api.Server) error {
+	if opts.http == "" {
+		return nil
+	}
+	opts.cfg.OnStored = func(uint64) { server.Notify() }
+	server.OnEquivocation(func(chain string, _ api.Event) {
+		opts.metrics.Equivocation(chain)
+	})
+	server.OnRejection(func(chain, reason string) {
+		opts.metrics.Reject(chain, reason)
+	})
+	mux := http.NewServeMux()
+	mux.Handle("GET /metrics", opts.metrics.Handler())
+	mux.Handle("/", server)
+	ln, err := net.Listen("tcp", opts.http)
+	if err != nil {
+		return err
+	}
+	srv := &http.Server{Handler: mux}
+	go func() {
+		<-ctx.Done()
+		srv.Close()
+	}()
+	go func() {
+		if err := srv.Serve(ln); !errors.Is(err, http.ErrServerClosed) {
+			opts.logger.Printf("http: %v", err)
+		}
+	}()
+	opts.logger.Printf("serving API on %s", ln.Addr())
+	return nil
+}
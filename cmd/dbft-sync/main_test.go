@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/txhsl/dbft-verifier/store"
+	"github.com/txhsl/dbft-verifier/syncer"
+	n3rpctest "github.com/txhsl/n3-dbft-verifier/rpctest"
+	neox "github.com/txhsl/neox-dbft-verifier"
+	neoxrpctest "github.com/txhsl/neox-dbft-verifier/rpctest"
+)
+
+func TestRun(t *testing.T) {
+	const headers = "../../bundle/testdata/n3_headers.json"
+	data, err := os.ReadFile(headers)
+	require.NoError(t, err)
+	var raws []json.RawMessage
+	require.NoError(t, json.Unmarshal(data, &raws))
+	server, err := n3rpctest.NewServer(raws[:1])
+	require.NoError(t, err)
+	defer server.Close()
+	trusted := filepath.Join(t.TempDir(), "trusted.json")
+	require.NoError(t, os.WriteFile(trusted, raws[0], 0o644))
+	db := filepath.Join(t.TempDir(), "db")
+
+	sync := func(args ...string) string {
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		defer cancel()
+		var stderr bytes.Buffer
+		args = append([]string{"n3", "-rpc", server.URL, "-db", db, "-poll", "1ms", "-report", "10ms"}, args...)
+		require.ErrorIs(t, run(ctx, args, &stderr), context.DeadlineExceeded)
+		return stderr.String()
+	}
+	// Synced at the trusted header
+	out := sync("-trusted", trusted)
+	require.Equal(t, true, strings.Contains(out, "height=9999 tip=9999 lag=0"))
+
+	// New block, resumed without the trusted header
+	require.NoError(t, server.AddHeader(raws[1]))
+	out = sync()
+	require.Equal(t, true, strings.Contains(out, "height=10000 tip=10000 lag=0"))
+	st, err := store.Open(db)
+	require.NoError(t, err)
+	defer st.Close()
+	height, _, err := st.Head()
+	require.NoError(t, err)
+	require.Equal(t, uint64(10000), height)
+
+	require.Error(t, run(context.Background(), []string{"n3", "-db", db}, &bytes.Buffer{}))
+	require.ErrorContains(t, run(context.Background(), []string{"neox", "-rpc", "http://127.0.0.1:1", "-db", db, "-extra-fork", "0:3"}, &bytes.Buffer{}), "unexpected extra version 3")
+	require.Error(t, run(context.Background(), []string{"eth"}, &bytes.Buffer{}))
+	require.Error(t, run(context.Background(), nil, &bytes.Buffer{}))
+}
+
+func TestRunNeoXConfig(t *testing.T) {
+	data, err := os.ReadFile("../../bundle/testdata/neox_headers.json")
+	require.NoError(t, err)
+	var raws []json.RawMessage
+	require.NoError(t, json.Unmarshal(data, &raws))
+	server, err := neoxrpctest.NewServer(raws[:1])
+	require.NoError(t, err)
+	defer server.Close()
+	trusted := filepath.Join(t.TempDir(), "trusted.json")
+	require.NoError(t, os.WriteFile(trusted, raws[0], 0o644))
+	db := filepath.Join(t.TempDir(), "db")
+
+	sync := func(args ...string) error {
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		defer cancel()
+		args = append([]string{"neox", "-rpc", server.URL, "-db", db, "-poll", "1ms"}, args...)
+		return run(ctx, args, &bytes.Buffer{})
+	}
+	// The config is stored and used on resume without flags
+	require.ErrorIs(t, sync("-trusted", trusted, "-extra-fork", "0:1:1", "-ecdsa-fallback"), context.DeadlineExceeded)
+	require.ErrorIs(t, sync(), context.DeadlineExceeded)
+	require.ErrorIs(t, sync("-no-extra-schedule"), syncer.ErrConfigMismatch)
+
+	st, err := store.Open(db)
+	require.NoError(t, err)
+	defer st.Close()
+	config, err := syncer.LoadNeoXConfig(st)
+	require.NoError(t, err)
+	require.Equal(t, neox.ExtraSchedule{{Block: 0, Version: neox.ExtraV1, Schemes: []byte{neox.ExtraV1ThresholdScheme}, AllowECDSAFallback: true}}, config.Extra)
+}
@@ -0,0 +1,67 @@
+// Command dbft-verify verifies offline header chains of N3 and Neo X.
+//
+// Usage:
+//
+//	dbft-verify n3 [flags] <input>...
+//	dbft-verify neox [flags] <input>...
+//
+// Inputs are JSON files, either a single header, an array or newline-delimited
+// headers, or directories of such files. Headers are in getblockheader (verbose) or
+// eth_getBlockByNumber format, optionally wrapped into JSON-RPC responses. Headers are
+// verified as a chain ordered by height, starting from the first one or -trusted. The
+// command exits with 1 on the first failure and with 2 on bad usage or input.
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+const usage = `usage: dbft-verify <n3|neox> [flags] <input>...`
+
+// verifyError is the failure of a header in the chain.
+type verifyError struct {
+	height uint64
+	hash   string
+	err    error
+}
+
+func (e *verifyError) Error() string {
+	return fmt.Sprintf("header %d (%s): %v", e.height, e.hash, e.err)
+}
+
+func (e *verifyError) Unwrap() error {
+	return e.err
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) < 1 {
+		fmt.Fprintln(stderr, usage)
+		return 2
+	}
+	var err error
+	switch args[0] {
+	case "n3":
+		err = runN3(args[1:], stdout, stderr)
+	case "neox":
+		err = runNeoX(args[1:], stdout, stderr)
+	default:
+		fmt.Fprintln(stderr, usage)
+		return 2
+	}
+	if err == nil {
+		return 0
+	}
+	fmt.Fprintln(stderr, err)
+	var verr *verifyError
+	if errors.As(err, &verr) {
+		return 1
+	}
+	return 2
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testN3Headers   = "../../bundle/testdata/n3_headers.json"
+	testNeoXHeaders = "../../bundle/testdata/neox_headers.json"
+)
+
+func runTest(args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := run(args, &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+// tamperTestHeaders writes headers of path modified by tamper.
+func tamperTestHeaders(t *testing.T, path string, tamper func(headers []map[string]any)) string {
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var headers []map[string]any
+	require.NoError(t, json.Unmarshal(data, &headers))
+	tamper(headers)
+	data, err = json.Marshal(headers)
+	require.NoError(t, err)
+	out := filepath.Join(t.TempDir(), "headers.json")
+	require.NoError(t, os.WriteFile(out, data, 0o644))
+	return out
+}
+
+func TestRun(t *testing.T) {
+	code, stdout, _ := runTest("n3", testN3Headers)
+	require.Equal(t, 0, code)
+	require.Equal(t, "verified 1 headers, head 10000 (0xd0e2c5cd98d58eeb66c4f8413a798a75e4adaca7f1e8862bf6c3ad9d671ee6f5)\n", stdout)
+	code, stdout, _ = runTest("neox", testNeoXHeaders)
+	require.Equal(t, 0, code)
+	require.Equal(t, true, strings.HasPrefix(stdout, "verified 2 headers"))
+
+	// Wrong network
+	code, _, stderr := runTest("n3", "-network", "894710606", testN3Headers)
+	require.Equal(t, 1, code)
+	require.Equal(t, true, strings.Contains(stderr, "header 10000"))
+	require.Equal(t, true, strings.Contains(stderr, "invalid multi-signature"))
+
+	// Tampered header
+	code, _, stderr = runTest("n3", tamperTestHeaders(t, testN3Headers, func(headers []map[string]any) {
+		headers[1]["witnesses"] = headers[0]["witnesses"]
+	}))
+	require.Equal(t, 1, code)
+	require.Equal(t, true, strings.Contains(stderr, "invalid multi-signature"))
+	code, _, stderr = runTest("neox", tamperTestHeaders(t, testNeoXHeaders, func(headers []map[string]any) {
+		headers[2]["mixHash"] = "0x0000000000000000000000000000000000000000000000000000000000000001"
+	}))
+	require.Equal(t, 1, code)
+	require.Equal(t, true, strings.Contains(stderr, "invalid seal signature"))
+
+	// Extra schedule
+	code, _, stderr = runTest("neox", "-extra-fork", "0:0", "-extra-fork", "0x1fdc40:1:0,1", testNeoXHeaders)
+	require.Equal(t, 1, code)
+	require.Equal(t, true, strings.Contains(stderr, "malformed extra"))
+	code, _, _ = runTest("neox", "-extra-fork", "0:0", "-extra-fork", "0x1fdc3f:1:0", "-extra-fork", "0x1fdc40:1:1", testNeoXHeaders)
+	require.Equal(t, 0, code)
+	code, _, _ = runTest("neox", "-extra-fork", "0:0", "-extra-fork", "0x1fdc3f:1:0", "-ecdsa-fallback", testNeoXHeaders)
+	require.Equal(t, 1, code)
+	code, _, _ = runTest("neox", "-extra-fork", "0:0", "-extra-fork", "0x1fdc3f:1:1", "-ecdsa-fallback", testNeoXHeaders)
+	require.Equal(t, 0, code)
+	code, _, _ = runTest("neox", "-extra-fork", "0:3", testNeoXHeaders)
+	require.Equal(t, 2, code)
+
+	// Directory of JSON-RPC responses
+	data, err := os.ReadFile(testNeoXHeaders)
+	require.NoError(t, err)
+	var raws []json.RawMessage
+	require.NoError(t, json.Unmarshal(data, &raws))
+	dir := t.TempDir()
+	for i, raw := range raws {
+		resp := `{"jsonrpc":"2.0","id":1,"result":` + string(raw) + `}`
+		require.NoError(t, os.WriteFile(filepath.Join(dir, strconv.Itoa(i)+".json"), []byte(resp), 0o644))
+	}
+	code, stdout, _ = runTest("neox", dir)
+	require.Equal(t, 0, code)
+	require.Equal(t, true, strings.HasPrefix(stdout, "verified 2 headers"))
+	// Gap
+	require.NoError(t, os.Remove(filepath.Join(dir, "1.json")))
+	code, _, stderr = runTest("neox", dir)
+	require.Equal(t, 2, code)
+	require.Equal(t, true, strings.Contains(stderr, "header not found"))
+
+	// Conflicting headers at the same height
+	code, _, stderr = runTest("neox", testNeoXHeaders, tamperTestHeaders(t, testNeoXHeaders, func(headers []map[string]any) {
+		headers[2]["gasUsed"] = "0x1"
+	}))
+	require.Equal(t, 2, code)
+	require.Equal(t, true, strings.Contains(stderr, "conflicting hashes"))
+
+	// Trusted header from another file
+	code, _, stderr = runTest("neox", "-trusted", testN3Headers, testNeoXHeaders)
+	require.Equal(t, 2, code)
+	require.NotEqual(t, "", stderr)
+
+	for _, args := range [][]string{nil, {"eth"}, {"n3"}, {"neox", "-extra-fork", "x"}, {"neox", "-extra-fork", "1:2:x"}} {
+		code, _, _ = runTest(args...)
+		require.Equal(t, 2, code)
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/block"
+	n3 "github.com/txhsl/n3-dbft-verifier"
+)
+
+func runN3(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("n3", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	network := fs.Uint("network", 860833102, "network magic")
+	trusted := fs.String("trusted", "", "file with the trusted header, the first input header by default")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	headers, err := decodeN3Headers(fs.Args())
+	if err != nil {
+		return err
+	}
+	var parent *block.Header
+	if *trusted != "" {
+		ts, err := decodeN3Headers([]string{*trusted})
+		if err != nil {
+			return err
+		}
+		parent = ts[len(ts)-1]
+	} else {
+		if len(headers) == 0 {
+			return errors.New("no headers")
+		}
+		parent, headers = headers[0], headers[1:]
+	}
+	for _, current := range headers {
+		if err := n3.CheckUpdateHeader(parent, current, uint32(*network)); err != nil {
+			return &verifyError{height: uint64(current.Index), hash: "0x" + current.Hash().StringLE(), err: err}
+		}
+		parent = current
+	}
+	fmt.Fprintf(stdout, "verified %d headers, head %d (%s)\n", len(headers), parent.Index, "0x"+parent.Hash().StringLE())
+	return nil
+}
+
+// decodeN3Headers returns the consecutive headers recorded in paths by index.
+func decodeN3Headers(paths []string) ([]*block.Header, error) {
+	src, err := n3.NewFileSource(paths...)
+	if err != nil {
+		return nil, err
+	}
+	ctx := context.Background()
+	tail, err := src.Tail(ctx)
+	if err != nil {
+		return nil, err
+	}
+	height, err := src.Height(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return src.Headers(ctx, tail, int(height-tail)+1)
+}
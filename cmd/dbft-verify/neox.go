@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/txhsl/dbft-verifier/cmd/internal/chainflags"
+	neox "github.com/txhsl/neox-dbft-verifier"
+)
+
+func runNeoX(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("neox", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	trusted := fs.String("trusted", "", "file with the trusted header, the first input header by default")
+	chainConfig := chainflags.NeoX(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	config, err := chainConfig()
+	if err != nil {
+		return err
+	}
+	headers, err := decodeNeoXHeaders(fs.Args())
+	if err != nil {
+		return err
+	}
+	var parent *types.Header
+	if *trusted != "" {
+		ts, err := decodeNeoXHeaders([]string{*trusted})
+		if err != nil {
+			return err
+		}
+		parent = ts[len(ts)-1]
+	} else {
+		if len(headers) == 0 {
+			return errors.New("no headers")
+		}
+		parent, headers = headers[0], headers[1:]
+	}
+	for _, current := range headers {
+		if err := neox.CheckUpdateHeader(config, parent, current); err != nil {
+			return &verifyError{height: current.Number.Uint64(), hash: current.Hash().String(), err: err}
+		}
+		parent = current
+	}
+	fmt.Fprintf(stdout, "verified %d headers, head %d (%s)\n", len(headers), parent.Number, parent.Hash())
+	return nil
+}
+
+// decodeNeoXHeaders returns the consecutive headers recorded in paths by number.
+func decodeNeoXHeaders(paths []string) ([]*types.Header, error) {
+	src, err := neox.NewFileSource(paths...)
+	if err != nil {
+		return nil, err
+	}
+	ctx := context.Background()
+	tail, err := src.Tail(ctx)
+	if err != nil {
+		return nil, err
+	}
+	height, err := src.Height(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return src.Headers(ctx, tail, int(height-tail)+1)
+}
@@ -0,0 +1,138 @@
+// Package chainflags registers the chain configuration flags shared by the commands.
+package chainflags
+
+import (
+	"flag"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	neox "github.com/txhsl/neox-dbft-verifier"
+)
+
+// NeoX registers Neo X fork schedule flags on fs, the returned function builds the
+// configuration once fs is parsed. Unset flags keep neox.DefaultChainConfig, -extra-fork
+// flags set the extra schedule.
+func NeoX(fs *flag.FlagSet) func() (*neox.ChainConfig, error) {
+	var (
+		config   = *neox.DefaultChainConfig
+		forks    extraForks
+		none     bool
+		fallback bool
+	)
+	fs.Var(optUint64{&config.ShanghaiTime}, "shanghai-time", "Shanghai activation time")
+	fs.Var(optUint64{&config.CancunTime}, "cancun-time", "Cancun activation time")
+	fs.Var(optUint64{&config.PragueTime}, "prague-time", "Prague activation time")
+	fs.Var(&forks, "extra-fork", "extra fork `block:version[:scheme,...]`, repeated in ascending block order")
+	fs.BoolVar(&none, "no-extra-schedule", false, "don't enforce the extra schedule")
+	fs.BoolVar(&fallback, "ecdsa-fallback", false, "allow ECDSA fallback blocks where the threshold scheme is active")
+	return func() (*neox.ChainConfig, error) {
+		if forks != nil {
+			config.Extra = neox.ExtraSchedule(forks)
+		}
+		if none {
+			config.Extra = nil
+		}
+		if fallback {
+			config.Extra = withECDSAFallback(config.Extra)
+		}
+		if err := config.Extra.Validate(); err != nil {
+			return nil, err
+		}
+		return &config, nil
+	}
+}
+
+// neoXFlags are the flags registered by NeoX.
+var neoXFlags = []string{"shanghai-time", "cancun-time", "prague-time", "extra-fork", "no-extra-schedule", "ecdsa-fallback"}
+
+// NeoXSet reports whether any flag registered by NeoX is set on the parsed fs.
+func NeoXSet(fs *flag.FlagSet) bool {
+	set := false
+	fs.Visit(func(f *flag.Flag) {
+		set = set || slices.Contains(neoXFlags, f.Name)
+	})
+	return set
+}
+
+// withECDSAFallback returns a copy of s allowing ECDSA fallback where the threshold
+// scheme is active.
+func withECDSAFallback(s neox.ExtraSchedule) neox.ExtraSchedule {
+	s = slices.Clone(s)
+	for i := range s {
+		if slices.Contains(s[i].Schemes, neox.ExtraV1ThresholdScheme) {
+			s[i].AllowECDSAFallback = true
+		}
+	}
+	return s
+}
+
+// extraForks is a repeated -extra-fork flag.
+type extraForks []neox.ExtraFork
+
+func (f *extraForks) String() string {
+	if f == nil {
+		return ""
+	}
+	parts := make([]string, len(*f))
+	for i, fork := range *f {
+		parts[i] = fmt.Sprintf("%d:%d", fork.Block, fork.Version)
+		for j, scheme := range fork.Schemes {
+			sep := ","
+			if j == 0 {
+				sep = ":"
+			}
+			parts[i] += sep + strconv.Itoa(int(scheme))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+func (f *extraForks) Set(s string) error {
+	parts := strings.Split(s, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return fmt.Errorf("extra fork %q is not block:version[:scheme,...]", s)
+	}
+	block, err := strconv.ParseUint(parts[0], 0, 64)
+	if err != nil {
+		return err
+	}
+	version, err := strconv.ParseUint(parts[1], 0, 8)
+	if err != nil {
+		return err
+	}
+	fork := neox.ExtraFork{Block: block, Version: byte(version)}
+	if len(parts) == 3 {
+		for _, p := range strings.Split(parts[2], ",") {
+			scheme, err := strconv.ParseUint(p, 0, 8)
+			if err != nil {
+				return err
+			}
+			fork.Schemes = append(fork.Schemes, byte(scheme))
+		}
+	}
+	*f = append(*f, fork)
+	return nil
+}
+
+// optUint64 is an optional uint64 flag, unset is nil.
+type optUint64 struct {
+	v **uint64
+}
+
+func (o optUint64) String() string {
+	if o.v == nil || *o.v == nil {
+		return ""
+	}
+	return strconv.FormatUint(**o.v, 10)
+}
+
+func (o optUint64) Set(s string) error {
+	v, err := strconv.ParseUint(s, 0, 64)
+	if err != nil {
+		return err
+	}
+	*o.v = &v
+	return nil
+}
@@ -0,0 +1,91 @@
+package metrics
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/nspcc-dev/neo-go/pkg/core/block"
+	"github.com/txhsl/dbft-verifier/reason"
+	"github.com/txhsl/dbft-verifier/syncer"
+	n3 "github.com/txhsl/n3-dbft-verifier"
+	neox "github.com/txhsl/neox-dbft-verifier"
+)
+
+// chain is syncer.Chain reporting source errors, its client reports the rest.
+type chain[H any] struct {
+	syncer.Chain[H]
+	m    *Metrics
+	name string
+}
+
+// InstrumentN3 returns c reporting source errors to m, its client is instrumented
+// with InstrumentN3Client.
+func InstrumentN3(m *Metrics, c *syncer.N3Chain) syncer.Chain[*block.Header] {
+	InstrumentN3Client(m, c.Client)
+	return &chain[*block.Header]{Chain: c, m: m, name: ChainN3}
+}
+
+// InstrumentNeoX returns c reporting source errors to m, its client is instrumented
+// with InstrumentNeoXClient.
+func InstrumentNeoX(m *Metrics, c *syncer.NeoXChain) syncer.Chain[*types.Header] {
+	InstrumentNeoXClient(m, c.Client)
+	return &chain[*types.Header]{Chain: c, m: m, name: ChainNeoX}
+}
+
+// InstrumentN3Client reports the head, verifications, rejections and validator set
+// changes of client to m.
+func InstrumentN3Client(m *Metrics, client *n3.LightClient) {
+	m.SetHead(ChainN3, uint64(client.Head().Index))
+	client.OnVerification(func(v n3.Verification) {
+		if v.Err != nil {
+			m.Reject(ChainN3, reason.Of(v.Err))
+			return
+		}
+		m.ObserveVerification(PathN3Multisig, v.Duration)
+		m.SetHead(ChainN3, uint64(v.Header.Index))
+	})
+	client.OnValidatorSetChange(func(n3.ValidatorSetChange) {
+		m.ValidatorSetChange(ChainN3)
+	})
+}
+
+// InstrumentNeoXClient reports the head, verifications, rejections and validator set
+// changes of client to m.
+func InstrumentNeoXClient(m *Metrics, client *neox.LightClient) {
+	m.SetHead(ChainNeoX, client.Head().Number.Uint64())
+	client.OnVerification(func(v neox.Verification) {
+		if v.Err != nil {
+			m.Reject(ChainNeoX, reason.Of(v.Err))
+			return
+		}
+		m.ObserveVerification(NeoXPath(v.Header), v.Duration)
+		m.SetHead(ChainNeoX, v.Header.Number.Uint64())
+	})
+	client.OnValidatorSetChange(func(neox.ValidatorSetChange) {
+		m.ValidatorSetChange(ChainNeoX)
+	})
+}
+
+// NeoXPath returns the verification path of h by its extra scheme.
+func NeoXPath(h *types.Header) string {
+	if len(h.Extra) > 1 && h.Extra[0] != neox.ExtraV0 && h.Extra[1] == neox.ExtraV1ThresholdScheme {
+		return PathNeoXBLS
+	}
+	return PathNeoXECDSA
+}
+
+func (c *chain[H]) Tip(ctx context.Context) (uint64, error) {
+	tip, err := c.Chain.Tip(ctx)
+	if err != nil && ctx.Err() == nil {
+		c.m.SourceError(c.name)
+	}
+	return tip, err
+}
+
+func (c *chain[H]) Fetch(ctx context.Context, from uint64, count int) ([]H, error) {
+	headers, err := c.Chain.Fetch(ctx, from, count)
+	if err != nil && ctx.Err() == nil {
+		c.m.SourceError(c.name)
+	}
+	return headers, err
+}
@@ -0,0 +1,81 @@
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/nspcc-dev/neo-go/pkg/core/block"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"github.com/txhsl/dbft-verifier/store"
+	"github.com/txhsl/dbft-verifier/syncer"
+	n3 "github.com/txhsl/n3-dbft-verifier"
+	n3rpctest "github.com/txhsl/n3-dbft-verifier/rpctest"
+	neox "github.com/txhsl/neox-dbft-verifier"
+)
+
+func TestInstrumentN3(t *testing.T) {
+	data, err := os.ReadFile("../bundle/testdata/n3_headers.json")
+	require.NoError(t, err)
+	var raws []json.RawMessage
+	require.NoError(t, json.Unmarshal(data, &raws))
+	server, err := n3rpctest.NewServer(raws)
+	require.NoError(t, err)
+	defer server.Close()
+	headers := make([]*block.Header, len(raws))
+	for i := range raws {
+		headers[i] = new(block.Header)
+		require.NoError(t, headers[i].UnmarshalJSON(raws[i]))
+	}
+	source := n3.NewRPCSource(server.URL)
+	source.Retries = 0
+
+	m := New()
+	chain := InstrumentN3(m, &syncer.N3Chain{Client: n3.NewLightClient(headers[0], 860833102), Source: source})
+	require.Equal(t, float64(9999), testutil.ToFloat64(m.head.WithLabelValues(ChainN3)))
+	server.FailNext(2)
+	_, err = chain.Tip(context.Background())
+	require.Error(t, err)
+	_, err = chain.Fetch(context.Background(), 10000, 1)
+	require.Error(t, err)
+	require.Equal(t, float64(2), testutil.ToFloat64(m.sourceErrors.WithLabelValues(ChainN3)))
+
+	require.NoError(t, chain.Update(headers[1], nil))
+	require.Equal(t, float64(10000), testutil.ToFloat64(m.head.WithLabelValues(ChainN3)))
+	require.Equal(t, 1, testutil.CollectAndCount(m.verification))
+
+	// Wrong network
+	chain = InstrumentN3(m, &syncer.N3Chain{Client: n3.NewLightClient(headers[0], 894710606), Source: source})
+	require.ErrorIs(t, chain.Update(headers[1], nil), n3.ErrSignature)
+	require.Equal(t, float64(1), testutil.ToFloat64(m.rejections.WithLabelValues(ChainN3, "invalid_signature")))
+}
+
+func TestInstrumentNeoX(t *testing.T) {
+	data, err := os.ReadFile("../bundle/testdata/neox_headers.json")
+	require.NoError(t, err)
+	var headers []*types.Header
+	require.NoError(t, json.Unmarshal(data, &headers))
+
+	m := New()
+	st := store.NewMemory()
+	client, err := syncer.ResumeNeoX(st, headers[0], neox.DefaultChainConfig)
+	require.NoError(t, err)
+	chain := InstrumentNeoX(m, &syncer.NeoXChain{Client: client})
+	require.NoError(t, chain.Update(headers[1], nil))
+	require.Equal(t, float64(headers[1].Number.Uint64()), testutil.ToFloat64(m.head.WithLabelValues(ChainNeoX)))
+	require.Equal(t, 1, testutil.CollectAndCount(m.verification, "dbft_verification_seconds"))
+	require.Error(t, chain.Update(headers[1], nil))
+	require.Equal(t, float64(1), testutil.ToFloat64(m.rejections.WithLabelValues(ChainNeoX, "parent_hash_mismatch")))
+}
+
+func TestNeoXPath(t *testing.T) {
+	header := &types.Header{Extra: []byte{neox.ExtraV0}}
+	require.Equal(t, PathNeoXECDSA, NeoXPath(header))
+	header.Extra = []byte{neox.ExtraV1, neox.ExtraV1ECDSAScheme}
+	require.Equal(t, PathNeoXECDSA, NeoXPath(header))
+	header.Extra = []byte{neox.ExtraV2, neox.ExtraV1ThresholdScheme}
+	require.Equal(t, PathNeoXBLS, NeoXPath(header))
+}
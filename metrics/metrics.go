@@ -0,0 +1,114 @@
+// Package metrics exports Prometheus metrics of header verification and sync.
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Chain labels.
+const (
+	ChainN3   = "n3"
+	ChainNeoX = "neox"
+)
+
+// Verification path labels.
+const (
+	PathN3Multisig = "n3_multisig"
+	PathNeoXECDSA  = "neox_ecdsa"
+	PathNeoXBLS    = "neox_bls"
+)
+
+// Metrics is a registry of verification and sync metrics.
+type Metrics struct {
+	registry            *prometheus.Registry
+	head                *prometheus.GaugeVec
+	verification        *prometheus.HistogramVec
+	rejections          *prometheus.CounterVec
+	validatorSetChanges *prometheus.CounterVec
+	equivocations       *prometheus.CounterVec
+	sourceErrors        *prometheus.CounterVec
+}
+
+// New returns Metrics registered together with the Go runtime and process collectors.
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		head: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "dbft",
+			Name:      "head_height",
+			Help:      "Height of the verified head.",
+		}, []string{"chain"}),
+		verification: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "dbft",
+			Name:      "verification_seconds",
+			Help:      "Latency of accepted header verifications.",
+			Buckets:   prometheus.ExponentialBuckets(0.00005, 2, 14),
+		}, []string{"path"}),
+		rejections: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "dbft",
+			Name:      "rejections_total",
+			Help:      "Headers and bundles rejected by sync or the verify API by reason.",
+		}, []string{"chain", "reason"}),
+		validatorSetChanges: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "dbft",
+			Name:      "validator_set_changes_total",
+			Help:      "Validator set changes of verified headers.",
+		}, []string{"chain"}),
+		equivocations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "dbft",
+			Name:      "equivocations_total",
+			Help:      "Valid headers conflicting with verified ones.",
+		}, []string{"chain"}),
+		sourceErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "dbft",
+			Name:      "source_errors_total",
+			Help:      "Errors of header sources.",
+		}, []string{"chain"}),
+	}
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.head,
+		m.verification,
+		m.rejections,
+		m.validatorSetChanges,
+		m.equivocations,
+		m.sourceErrors,
+	)
+	return m
+}
+
+// Handler returns the handler of the /metrics endpoint.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+func (m *Metrics) SetHead(chain string, height uint64) {
+	m.head.WithLabelValues(chain).Set(float64(height))
+}
+
+func (m *Metrics) ObserveVerification(path string, d time.Duration) {
+	m.verification.WithLabelValues(path).Observe(d.Seconds())
+}
+
+// Reject counts a rejected header, reason is a reason of reason.Of.
+func (m *Metrics) Reject(chain, reason string) {
+	m.rejections.WithLabelValues(chain, reason).Inc()
+}
+
+func (m *Metrics) ValidatorSetChange(chain string) {
+	m.validatorSetChanges.WithLabelValues(chain).Inc()
+}
+
+func (m *Metrics) Equivocation(chain string) {
+	m.equivocations.WithLabelValues(chain).Inc()
+}
+
+func (m *Metrics) SourceError(chain string) {
+	m.sourceErrors.WithLabelValues(chain).Inc()
+}
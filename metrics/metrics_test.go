@@ -0,0 +1,45 @@
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetrics(t *testing.T) {
+	m := New()
+	m.SetHead(ChainN3, 10)
+	m.ObserveVerification(PathNeoXBLS, time.Millisecond)
+	m.Reject(ChainNeoX, "invalid_signature")
+	m.Reject(ChainNeoX, "invalid_signature")
+	m.ValidatorSetChange(ChainN3)
+	m.Equivocation(ChainNeoX)
+	m.SourceError(ChainN3)
+	require.Equal(t, float64(10), testutil.ToFloat64(m.head.WithLabelValues(ChainN3)))
+	require.Equal(t, float64(2), testutil.ToFloat64(m.rejections.WithLabelValues(ChainNeoX, "invalid_signature")))
+	require.Equal(t, float64(1), testutil.ToFloat64(m.validatorSetChanges.WithLabelValues(ChainN3)))
+	require.Equal(t, float64(1), testutil.ToFloat64(m.equivocations.WithLabelValues(ChainNeoX)))
+	require.Equal(t, float64(1), testutil.ToFloat64(m.sourceErrors.WithLabelValues(ChainN3)))
+
+	server := httptest.NewServer(m.Handler())
+	defer server.Close()
+	resp, err := http.Get(server.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	for _, line := range []string{
+		`dbft_head_height{chain="n3"} 10`,
+		`dbft_verification_seconds_count{path="neox_bls"} 1`,
+		`dbft_rejections_total{chain="neox",reason="invalid_signature"} 2`,
+		"go_goroutines",
+	} {
+		require.Equal(t, true, strings.Contains(string(body), line), line)
+	}
+}
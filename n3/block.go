@@ -0,0 +1,61 @@
+package verifier
+
+import (
+	"errors"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/block"
+	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+)
+
+// Limits are the block limits of a protocol configuration.
+type Limits struct {
+	MaxBlockSize            int // Maximum block size in bytes.
+	MaxTransactionsPerBlock int // Maximum number of transactions per block.
+	ValidatorsCount         int // Number of consensus nodes signing a header.
+}
+
+// DefaultLimits are the limits of the N3 protocol configuration defaults, used by
+// MainNet and TestNet.
+var DefaultLimits = Limits{
+	MaxBlockSize:            262144,
+	MaxTransactionsPerBlock: 512,
+	ValidatorsCount:         7,
+}
+
+var (
+	ErrBlockLimits = errors.New("block exceeds limits")
+	ErrDuplicateTx = errors.New("duplicate transaction")
+	ErrMerkleRoot  = errors.New("merkle root mismatch")
+)
+
+func VerifyBlock(trustedParent *block.Header, b *block.Block, network uint32, limits Limits) bool {
+	return CheckBlock(trustedParent, b, network, limits) == nil
+}
+
+// CheckBlock is VerifyBlock returning the reason of the failure.
+func CheckBlock(trustedParent *block.Header, b *block.Block, network uint32, limits Limits) error {
+	if err := CheckUpdateHeader(trustedParent, &b.Header, network); err != nil {
+		return err
+	}
+	if int(b.PrimaryIndex) >= limits.ValidatorsCount {
+		return ErrPrimaryIndex
+	}
+	// Check limits
+	if len(b.Transactions) > limits.MaxTransactionsPerBlock || io.GetVarSize(b) > limits.MaxBlockSize {
+		return ErrBlockLimits
+	}
+	// Check txs
+	seen := make(map[util.Uint256]struct{}, len(b.Transactions))
+	for _, tx := range b.Transactions {
+		h := tx.Hash()
+		if _, ok := seen[h]; ok {
+			return ErrDuplicateTx
+		}
+		seen[h] = struct{}{}
+	}
+	if b.ComputeMerkleRoot() != b.MerkleRoot {
+		return ErrMerkleRoot
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package verifier
+
+import (
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/block"
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyBlock(t *testing.T) {
+	parent := new(block.Header)
+	err := parent.UnmarshalJSON([]byte(testParentHeader))
+	require.NoError(t, err)
+	current := new(block.Header)
+	err = current.UnmarshalJSON([]byte(testCurrentHeader))
+	require.NoError(t, err)
+	b := &block.Block{Header: *current}
+	require.Equal(t, true, VerifyBlock(parent, b, 860833102, DefaultLimits))
+
+	// Txs not committed by the header
+	tx := transaction.New([]byte{0x11}, 0)
+	b.Transactions = []*transaction.Transaction{tx}
+	require.ErrorIs(t, CheckBlock(parent, b, 860833102, DefaultLimits), ErrMerkleRoot)
+	b.Transactions = make([]*transaction.Transaction, DefaultLimits.MaxTransactionsPerBlock+1)
+	require.ErrorIs(t, CheckBlock(parent, b, 860833102, DefaultLimits), ErrBlockLimits)
+
+	// Limits of another configuration
+	b.Transactions = nil
+	limits := DefaultLimits
+	limits.ValidatorsCount = int(b.PrimaryIndex)
+	require.ErrorIs(t, CheckBlock(parent, b, 860833102, limits), ErrPrimaryIndex)
+	limits = DefaultLimits
+	limits.MaxBlockSize = io.GetVarSize(b) - 1
+	require.Equal(t, false, VerifyBlock(parent, b, 860833102, limits))
+	require.ErrorIs(t, CheckBlock(parent, b, 860833102, limits), ErrBlockLimits)
+}
+
+func TestVerifyBlockDuplicateTx(t *testing.T) {
+	v := newTestValidators(t)
+	trusted := &block.Header{Timestamp: 1628062127819, NextConsensus: v.hash()}
+	tx := transaction.New([]byte{0x11}, 0)
+	// The header commits to the duplicated list
+	b := &block.Block{Header: *v.next(trusted, v.hash()), Transactions: []*transaction.Transaction{tx, tx}}
+	b.MerkleRoot = b.ComputeMerkleRoot()
+	v.sign(&b.Header, 0, 1, 2, 3, 4)
+	require.NoError(t, CheckUpdateHeader(trusted, &b.Header, testNetwork))
+	require.ErrorIs(t, CheckBlock(trusted, b, testNetwork, DefaultLimits), ErrDuplicateTx)
+
+	b.Transactions = b.Transactions[:1]
+	require.ErrorIs(t, CheckBlock(trusted, b, testNetwork, DefaultLimits), ErrMerkleRoot)
+}
@@ -0,0 +1,156 @@
+package verifier
+
+import (
+	"crypto/elliptic"
+	"sync"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/block"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+)
+
+// ValidatorSetChange is emitted when a verified header changes NextConsensus.
+type ValidatorSetChange struct {
+	Height uint32
+	Old    util.Uint160
+	New    util.Uint160
+	// Keys are the validators of the old set that signed the change, the keys of
+	// the new set are only revealed by the script of the next header.
+	Keys keys.PublicKeys
+}
+
+// Verification is emitted for every header verified by Update, Err is the
+// CheckUpdateHeader error of a rejected header.
+type Verification struct {
+	Header   *block.Header
+	Duration time.Duration
+	Err      error
+}
+
+// LightClient follows the chain from a trusted header, accepting only headers that
+// pass VerifyUpdateHeader.
+type LightClient struct {
+	lock    sync.RWMutex
+	network uint32
+	head    *block.Header
+	history *MMR
+
+	// notify is held while delivering events, it's taken before releasing lock so
+	// events are delivered in the order of updates.
+	notify               sync.Mutex
+	onValidatorSetChange []func(ValidatorSetChange)
+	onVerification       []func(Verification)
+}
+
+func NewLightClient(trusted *block.Header, network uint32) *LightClient {
+	history := NewMMR()
+	history.Append(trusted.Hash())
+	return &LightClient{
+		network: network,
+		head:    trusted,
+		history: history,
+	}
+}
+
+// ResumeLightClient returns a light client following a verified head, history is the
+// accumulator of the verified headers up to head, e.g. rebuilt from a header store.
+func ResumeLightClient(head *block.Header, network uint32, history *MMR) *LightClient {
+	return &LightClient{
+		network: network,
+		head:    head,
+		history: history,
+	}
+}
+
+func (c *LightClient) Network() uint32 {
+	return c.network
+}
+
+// Head returns the latest verified header.
+func (c *LightClient) Head() *block.Header {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.head
+}
+
+// History returns the accumulator of verified header hashes, its i-th leaf is the
+// header at the trusted height plus i.
+func (c *LightClient) History() *MMR {
+	return c.history
+}
+
+// OnValidatorSetChange registers f to be called on every validator set transition.
+// Callbacks run synchronously in Update in the order of updates, they must not call
+// Update.
+func (c *LightClient) OnValidatorSetChange(f func(ValidatorSetChange)) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.onValidatorSetChange = append(c.onValidatorSetChange, f)
+}
+
+// OnVerification registers f to be called on every verified or rejected header,
+// headers failing to commit are not reported. Callbacks run synchronously in Update
+// in the order of updates, they must not call Update.
+func (c *LightClient) OnVerification(f func(Verification)) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.onVerification = append(c.onVerification, f)
+}
+
+// Update verifies header against the head and makes it the new head on success, it
+// returns the CheckUpdateHeader error otherwise.
+func (c *LightClient) Update(header *block.Header) error {
+	return c.UpdateWith(header, nil)
+}
+
+// UpdateWith is Update calling commit once header is verified and before it becomes
+// the head, e.g. to persist it. The head isn't changed if commit fails, its error is
+// returned then.
+func (c *LightClient) UpdateWith(header *block.Header, commit func() error) error {
+	c.lock.Lock()
+	parent := c.head
+	start := time.Now()
+	err := CheckUpdateHeader(parent, header, c.network)
+	verification := Verification{Header: header, Duration: time.Since(start), Err: err}
+	if err == nil && commit != nil {
+		if err := commit(); err != nil {
+			c.lock.Unlock()
+			return err
+		}
+	}
+	if err == nil {
+		c.head = header
+		c.history.Append(header.Hash())
+	}
+	callbacks, verified := c.onValidatorSetChange, c.onVerification
+	c.notify.Lock()
+	defer c.notify.Unlock()
+	c.lock.Unlock()
+
+	for _, f := range verified {
+		f(verification)
+	}
+	if err != nil {
+		return err
+	}
+
+	if header.NextConsensus != parent.NextConsensus {
+		event := ValidatorSetChange{
+			Height: header.Index,
+			Old:    parent.NextConsensus,
+			New:    header.NextConsensus,
+		}
+		pubs, _ := parseVerificationScript(header.Script.VerificationScript)
+		for _, pub := range pubs {
+			key, err := keys.NewPublicKeyFromBytes(pub, elliptic.P256())
+			if err == nil {
+				event.Keys = append(event.Keys, key)
+			}
+		}
+		for _, f := range callbacks {
+			f(event)
+		}
+	}
+	return nil
+}
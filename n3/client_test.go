@@ -0,0 +1,153 @@
+package verifier
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/block"
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/emit"
+	"github.com/stretchr/testify/require"
+)
+
+const testNetwork = 860833102
+
+type testValidators struct {
+	privs  []*keys.PrivateKey // Sorted by public key.
+	script []byte
+}
+
+func newTestValidators(t testing.TB) *testValidators {
+	privs := make([]*keys.PrivateKey, 7)
+	pubs := make(keys.PublicKeys, 7)
+	for i := range privs {
+		priv, err := keys.NewPrivateKey()
+		require.NoError(t, err)
+		privs[i] = priv
+	}
+	for i := range privs {
+		for j := i + 1; j < len(privs); j++ {
+			if privs[j].PublicKey().Cmp(privs[i].PublicKey()) < 0 {
+				privs[i], privs[j] = privs[j], privs[i]
+			}
+		}
+		pubs[i] = privs[i].PublicKey()
+	}
+	script, err := smartcontract.CreateMultiSigRedeemScript(5, pubs)
+	require.NoError(t, err)
+	return &testValidators{privs: privs, script: script}
+}
+
+func (v *testValidators) hash() util.Uint160 {
+	return transaction.Witness{VerificationScript: v.script}.ScriptHash()
+}
+
+// sign adds the witness of validators at indexes, that must be ascending.
+func (v *testValidators) sign(header *block.Header, indexes ...int) {
+	w := io.NewBufBinWriter()
+	for _, i := range indexes {
+		emit.Bytes(w.BinWriter, v.privs[i].SignHashable(testNetwork, header))
+	}
+	header.Script = transaction.Witness{InvocationScript: w.Bytes(), VerificationScript: v.script}
+}
+
+// next returns an unsigned child of parent.
+func (v *testValidators) next(parent *block.Header, nextConsensus util.Uint160) *block.Header {
+	return &block.Header{
+		PrevHash:      parent.Hash(),
+		Timestamp:     parent.Timestamp + 15000,
+		Index:         parent.Index + 1,
+		PrimaryIndex:  byte((parent.Index + 1) % uint32(DefaultLimits.ValidatorsCount)),
+		NextConsensus: nextConsensus,
+	}
+}
+
+func TestLightClient(t *testing.T) {
+	v := newTestValidators(t)
+	nextV := newTestValidators(t)
+	trusted := &block.Header{Timestamp: 1628062127819, NextConsensus: v.hash()}
+	client := NewLightClient(trusted, testNetwork)
+	var events []ValidatorSetChange
+	client.OnValidatorSetChange(func(e ValidatorSetChange) {
+		events = append(events, e)
+	})
+	var verifications []Verification
+	client.OnVerification(func(v Verification) {
+		verifications = append(verifications, v)
+	})
+
+	header := v.next(trusted, v.hash())
+	v.sign(header, 0, 1, 2, 3, 4)
+	// Failed commit keeps the head
+	errCommit := errors.New("commit")
+	require.ErrorIs(t, client.UpdateWith(header, func() error { return errCommit }), errCommit)
+	require.Equal(t, trusted, client.Head())
+	require.Equal(t, uint64(1), client.History().Leaves())
+	require.NoError(t, client.Update(header))
+	require.Equal(t, header, client.Head())
+	require.Empty(t, events)
+
+	// Validator set change
+	header = v.next(header, nextV.hash())
+	v.sign(header, 1, 2, 4, 5, 6)
+	require.NoError(t, client.Update(header))
+	require.Len(t, events, 1)
+	require.Equal(t, uint32(2), events[0].Height)
+	require.Equal(t, v.hash(), events[0].Old)
+	require.Equal(t, nextV.hash(), events[0].New)
+	require.Equal(t, 7, len(events[0].Keys))
+	require.Equal(t, v.privs[0].PublicKey(), events[0].Keys[0])
+
+	// Old set can not sign anymore
+	header = v.next(header, nextV.hash())
+	v.sign(header, 0, 1, 2, 3, 4)
+	require.ErrorIs(t, client.Update(header), ErrNextConsensus)
+	nextV.sign(header, 0, 1, 2, 3, 4)
+	require.NoError(t, client.Update(header))
+	require.Len(t, events, 1)
+
+	// The failed commit is not reported
+	require.Len(t, verifications, 4)
+	require.ErrorIs(t, verifications[2].Err, ErrNextConsensus)
+	require.Equal(t, header, verifications[3].Header)
+	require.NoError(t, verifications[3].Err)
+}
+
+func TestLightClientEventOrder(t *testing.T) {
+	v := newTestValidators(t)
+	nextV := newTestValidators(t)
+	trusted := &block.Header{Timestamp: 1628062127819, NextConsensus: v.hash()}
+	client := NewLightClient(trusted, testNetwork)
+	h1 := v.next(trusted, nextV.hash())
+	v.sign(h1, 0, 1, 2, 3, 4)
+	h2 := v.next(h1, v.hash())
+	nextV.sign(h2, 0, 1, 2, 3, 4)
+
+	var (
+		lock    sync.Mutex
+		heights []uint32
+	)
+	delivering := make(chan struct{})
+	client.OnValidatorSetChange(func(e ValidatorSetChange) {
+		if e.Height == 1 {
+			close(delivering)
+			// Let the next update run before the event is recorded
+			time.Sleep(50 * time.Millisecond)
+		}
+		lock.Lock()
+		heights = append(heights, e.Height)
+		lock.Unlock()
+	})
+	done := make(chan error)
+	go func() { done <- client.Update(h1) }()
+	<-delivering
+	require.NoError(t, client.Update(h2))
+	require.NoError(t, <-done)
+	require.Equal(t, []uint32{1, 2}, heights)
+}
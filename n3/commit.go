@@ -0,0 +1,212 @@
+package verifier
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/block"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neo-go/pkg/network/payload"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
+)
+
+// dBFT message types, ref https://github.com/nspcc-dev/neo-go/blob/v0.108.1/pkg/consensus/payload.go#L37
+const (
+	commitType          byte = 0x30
+	prepareRequestType  byte = 0x20
+	recoveryMessageType byte = 0x41
+
+	maxValidatorsCount = 255 // Validator index is a byte.
+)
+
+// Commit is a dBFT Commit of a validator, i.e. its signature of the proposed header.
+type Commit struct {
+	BlockIndex     uint32
+	ValidatorIndex byte
+	ViewNumber     byte
+	Signature      []byte
+}
+
+// DecodeCommits returns the commits carried by a dBFT Commit or RecoveryMessage
+// payload, after checking the payload is signed by the validator it claims to be
+// sent by. Validators are in the order of the verification script.
+func DecodeCommits(p *payload.Extensible, validators keys.PublicKeys, network uint32, stateRootInHeader bool) ([]Commit, error) {
+	if p.Category != payload.ConsensusCategory {
+		return nil, errors.New("not a consensus payload")
+	}
+	r := io.NewBinReaderFromBuf(p.Data)
+	typ := r.ReadB()
+	blockIndex := r.ReadU32LE()
+	validatorIndex := r.ReadB()
+	viewNumber := r.ReadB()
+	if r.Err != nil {
+		return nil, r.Err
+	}
+	// Check payload witness
+	if int(validatorIndex) >= len(validators) {
+		return nil, errors.New("validator index out of range")
+	}
+	sender := validators[validatorIndex]
+	if p.Sender != sender.GetScriptHash() || !bytes.Equal(p.Witness.VerificationScript, sender.GetVerificationScript()) {
+		return nil, errors.New("unexpected sender")
+	}
+	inv := p.Witness.InvocationScript
+	if len(inv) != SignatureDataLen || inv[0] != byte(opcode.PUSHDATA1) || inv[1] != byte(SignatureLen) {
+		return nil, errors.New("malformed witness")
+	}
+	if !sender.VerifyHashable(inv[2:], network, p) {
+		return nil, errors.New("invalid witness")
+	}
+	switch typ {
+	case commitType:
+		sig := make([]byte, SignatureLen)
+		r.ReadBytes(sig)
+		if r.Err != nil {
+			return nil, r.Err
+		}
+		return []Commit{{
+			BlockIndex:     blockIndex,
+			ValidatorIndex: validatorIndex,
+			ViewNumber:     viewNumber,
+			Signature:      sig,
+		}}, nil
+	case recoveryMessageType:
+		return decodeRecoveryCommits(r, blockIndex, stateRootInHeader)
+	default:
+		return nil, fmt.Errorf("unexpected message type 0x%02x", typ)
+	}
+}
+
+// decodeRecoveryCommits skips to the commits of a RecoveryMessage, ref
+// https://github.com/nspcc-dev/neo-go/blob/v0.108.1/pkg/consensus/recovery_message.go#L47
+func decodeRecoveryCommits(r *io.BinReader, blockIndex uint32, stateRootInHeader bool) ([]Commit, error) {
+	// ChangeView payloads
+	for range readCount(r, maxValidatorsCount) {
+		r.ReadB()
+		r.ReadB()
+		r.ReadU64LE()
+		r.ReadVarBytes(1024)
+	}
+	// PrepareRequest or preparation hash
+	if r.ReadBool() {
+		if r.ReadB() != prepareRequestType && r.Err == nil {
+			r.Err = errors.New("recovery message PrepareRequest has wrong type")
+		}
+		r.ReadU32LE()
+		r.ReadB()
+		r.ReadB()
+		r.ReadU32LE()
+		r.ReadBytes(make([]byte, util.Uint256Size))
+		r.ReadU64LE()
+		r.ReadU64LE()
+		for range readCount(r, block.MaxTransactionsPerBlock) {
+			r.ReadBytes(make([]byte, util.Uint256Size))
+		}
+		if stateRootInHeader {
+			r.ReadBytes(make([]byte, util.Uint256Size))
+		}
+	} else if l := r.ReadVarUint(); l != 0 {
+		if l != util.Uint256Size && r.Err == nil {
+			r.Err = errors.New("invalid preparation hash")
+		}
+		r.ReadBytes(make([]byte, util.Uint256Size))
+	}
+	// Preparation payloads
+	for range readCount(r, maxValidatorsCount) {
+		r.ReadB()
+		r.ReadVarBytes(1024)
+	}
+	// Commit payloads
+	n := readCount(r, maxValidatorsCount)
+	if r.Err != nil {
+		return nil, r.Err
+	}
+	commits := make([]Commit, n)
+	for i := range commits {
+		commits[i].BlockIndex = blockIndex
+		commits[i].ViewNumber = r.ReadB()
+		commits[i].ValidatorIndex = r.ReadB()
+		commits[i].Signature = make([]byte, SignatureLen)
+		r.ReadBytes(commits[i].Signature)
+		r.ReadVarBytes(1024)
+	}
+	if r.Err != nil {
+		return nil, r.Err
+	}
+	return commits, nil
+}
+
+func readCount(r *io.BinReader, max int) int {
+	n := r.ReadVarUint()
+	if r.Err != nil {
+		return 0
+	}
+	if n > uint64(max) {
+		r.Err = errors.New("array is too big")
+		return 0
+	}
+	return int(n)
+}
+
+// VerifyCommit checks that c is a signature of header by its validator.
+func VerifyCommit(c *Commit, header *block.Header, validators keys.PublicKeys, network uint32) bool {
+	if c.BlockIndex != header.Index || int(c.ValidatorIndex) >= len(validators) {
+		return false
+	}
+	return validators[c.ValidatorIndex].VerifyHashable(c.Signature, network, header)
+}
+
+// IsCommitEquivocation reports whether commits a of header ha and b of header hb are
+// signed by the same validator for different headers at the same height. Both commits
+// must be valid for their own headers. Signatures aren't compared: ECDSA signatures
+// are randomized and malleable, so another signature of the same header isn't an
+// equivocation.
+func IsCommitEquivocation(a *Commit, ha *block.Header, b *Commit, hb *block.Header, validators keys.PublicKeys, network uint32) bool {
+	if a.ValidatorIndex != b.ValidatorIndex || ha.Index != hb.Index || ha.Hash() == hb.Hash() {
+		return false
+	}
+	return VerifyCommit(a, ha, validators, network) && VerifyCommit(b, hb, validators, network)
+}
+
+// BuildInvocationScript builds the header invocation script from the valid commits,
+// ordered by validator index as vm.CheckMultisigPar expects.
+func BuildInvocationScript(header *block.Header, commits []Commit, validators keys.PublicKeys, network uint32) ([]byte, error) {
+	m := len(validators) - (len(validators)-1)/3
+	sigs := make(map[byte][]byte)
+	for i := range commits {
+		if _, ok := sigs[commits[i].ValidatorIndex]; ok {
+			continue
+		}
+		if VerifyCommit(&commits[i], header, validators, network) {
+			sigs[commits[i].ValidatorIndex] = commits[i].Signature
+		}
+	}
+	if len(sigs) < m {
+		return nil, fmt.Errorf("%d valid commits of %d required", len(sigs), m)
+	}
+	indexes := make([]byte, 0, len(sigs))
+	for i := range sigs {
+		indexes = append(indexes, i)
+	}
+	sort.Slice(indexes, func(i, j int) bool {
+		return indexes[i] < indexes[j]
+	})
+	ordered := make([][]byte, m)
+	for i := range ordered {
+		ordered[i] = sigs[indexes[i]]
+	}
+	return buildInvocationScript(ordered), nil
+}
+
+func buildInvocationScript(sigs [][]byte) []byte {
+	script := make([]byte, 0, len(sigs)*SignatureDataLen)
+	for _, sig := range sigs {
+		script = append(script, byte(opcode.PUSHDATA1), byte(len(sig)))
+		script = append(script, sig...)
+	}
+	return script
+}
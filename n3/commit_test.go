@@ -0,0 +1,111 @@
+package verifier
+
+import (
+	"crypto/elliptic"
+	"math/big"
+	"slices"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/block"
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neo-go/pkg/network/payload"
+	"github.com/nspcc-dev/neo-go/pkg/vm/emit"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestConsensusPayload(t *testing.T, priv *keys.PrivateKey, data []byte) *payload.Extensible {
+	p := &payload.Extensible{
+		Category:      payload.ConsensusCategory,
+		ValidBlockEnd: 100,
+		Sender:        priv.GetScriptHash(),
+		Data:          data,
+	}
+	w := io.NewBufBinWriter()
+	emit.Bytes(w.BinWriter, priv.SignHashable(testNetwork, p))
+	require.NoError(t, w.Err)
+	p.Witness = transaction.Witness{InvocationScript: w.Bytes(), VerificationScript: priv.PublicKey().GetVerificationScript()}
+	return p
+}
+
+func TestCommits(t *testing.T) {
+	v := newTestValidators(t)
+	validators := make(keys.PublicKeys, len(v.privs))
+	for i := range v.privs {
+		validators[i] = v.privs[i].PublicKey()
+	}
+	parent := v.next(&block.Header{}, v.hash())
+	header := v.next(parent, v.hash())
+
+	// Commits of 4 validators, not enough
+	var commits []Commit
+	for _, i := range []byte{6, 1, 3, 5} {
+		w := io.NewBufBinWriter()
+		w.WriteB(commitType)
+		w.WriteU32LE(header.Index)
+		w.WriteB(i)
+		w.WriteB(0)
+		w.WriteBytes(v.privs[i].SignHashable(testNetwork, header))
+		decoded, err := DecodeCommits(newTestConsensusPayload(t, v.privs[i], w.Bytes()), validators, testNetwork, false)
+		require.NoError(t, err)
+		require.Len(t, decoded, 1)
+		require.Equal(t, true, VerifyCommit(&decoded[0], header, validators, testNetwork))
+		commits = append(commits, decoded...)
+	}
+	_, err := BuildInvocationScript(header, commits, validators, testNetwork)
+	require.Error(t, err)
+
+	// Recovery message with another commit
+	w := io.NewBufBinWriter()
+	w.WriteB(recoveryMessageType)
+	w.WriteU32LE(header.Index)
+	w.WriteB(2)
+	w.WriteB(0)
+	w.WriteVarUint(0)  // ChangeView payloads
+	w.WriteBool(false) // No PrepareRequest
+	w.WriteVarUint(32) // Preparation hash
+	w.WriteBytes(make([]byte, 32))
+	w.WriteVarUint(0) // Preparation payloads
+	w.WriteVarUint(2) // Commit payloads
+	for _, i := range []byte{0, 6} {
+		w.WriteB(0)
+		w.WriteB(i)
+		w.WriteBytes(v.privs[i].SignHashable(testNetwork, header))
+		w.WriteVarBytes([]byte{0x0c})
+	}
+	recovered, err := DecodeCommits(newTestConsensusPayload(t, v.privs[2], w.Bytes()), validators, testNetwork, false)
+	require.NoError(t, err)
+	require.Len(t, recovered, 2)
+	commits = append(commits, recovered...)
+
+	// Rebuilt witness is accepted
+	inv, err := BuildInvocationScript(header, commits, validators, testNetwork)
+	require.NoError(t, err)
+	header.Script = transaction.Witness{InvocationScript: inv, VerificationScript: v.script}
+	require.Equal(t, true, VerifyUpdateHeader(parent, header, testNetwork))
+
+	// Forged sender
+	_, err = DecodeCommits(newTestConsensusPayload(t, v.privs[3], w.Bytes()), validators, testNetwork, false)
+	require.Error(t, err)
+
+	// Equivocation
+	other := v.next(parent, v.hash())
+	other.Timestamp++
+	equivocation := commits[0]
+	equivocation.Signature = v.privs[equivocation.ValidatorIndex].SignHashable(testNetwork, other)
+	require.Equal(t, false, VerifyCommit(&equivocation, header, validators, testNetwork))
+	require.Equal(t, true, IsCommitEquivocation(&commits[0], header, &equivocation, other, validators, testNetwork))
+	require.Equal(t, false, IsCommitEquivocation(&commits[0], header, &commits[1], other, validators, testNetwork))
+	// Both must be valid, a relayed commit can't frame the validator
+	require.Equal(t, false, IsCommitEquivocation(&commits[0], header, &commits[0], other, validators, testNetwork))
+
+	// Malleated (r, n-s) signature of the same header
+	malleated := commits[0]
+	s := new(big.Int).SetBytes(commits[0].Signature[32:])
+	s.Sub(elliptic.P256().Params().N, s)
+	malleated.Signature = append(slices.Clone(commits[0].Signature[:32]), s.FillBytes(make([]byte, 32))...)
+	require.NotEqual(t, commits[0].Signature, malleated.Signature)
+	require.Equal(t, true, VerifyCommit(&malleated, header, validators, testNetwork))
+	require.Equal(t, false, IsCommitEquivocation(&commits[0], header, &malleated, header, validators, testNetwork))
+}
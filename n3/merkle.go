@@ -0,0 +1,54 @@
+package verifier
+
+import (
+	"errors"
+
+	"github.com/nspcc-dev/neo-go/pkg/crypto/hash"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+)
+
+// MerkleProof returns the path of the hash at index to the MerkleRoot of hashes, as
+// computed by hash.CalcMerkleRoot.
+func MerkleProof(hashes []util.Uint256, index int) ([]util.Uint256, error) {
+	if index < 0 || index >= len(hashes) {
+		return nil, errors.New("index out of range")
+	}
+	var path []util.Uint256
+	level := hashes
+	for len(level) > 1 {
+		sibling := index ^ 1
+		if sibling == len(level) {
+			sibling = index
+		}
+		path = append(path, level[sibling])
+		parents := make([]util.Uint256, (len(level)+1)/2)
+		for i := range parents {
+			right := level[i*2]
+			if i*2+1 < len(level) {
+				right = level[i*2+1]
+			}
+			parents[i] = hashMerkleNode(level[i*2], right)
+		}
+		level = parents
+		index /= 2
+	}
+	return path, nil
+}
+
+// VerifyMerkleProof checks that leaf is at index under root.
+func VerifyMerkleProof(root util.Uint256, leaf util.Uint256, index uint32, path []util.Uint256) bool {
+	node := leaf
+	for _, sibling := range path {
+		if index%2 == 0 {
+			node = hashMerkleNode(node, sibling)
+		} else {
+			node = hashMerkleNode(sibling, node)
+		}
+		index /= 2
+	}
+	return index == 0 && node == root
+}
+
+func hashMerkleNode(left, right util.Uint256) util.Uint256 {
+	return hash.DoubleSha256(append(left.BytesBE(), right.BytesBE()...))
+}
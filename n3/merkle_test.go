@@ -0,0 +1,30 @@
+package verifier
+
+import (
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/crypto/hash"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerkleProof(t *testing.T) {
+	for n := 1; n <= 7; n++ {
+		hashes := make([]util.Uint256, n)
+		for i := range hashes {
+			hashes[i] = hash.Sha256([]byte{byte(i)})
+		}
+		root := hash.CalcMerkleRoot(append([]util.Uint256{}, hashes...))
+		for i := range hashes {
+			path, err := MerkleProof(hashes, i)
+			require.NoError(t, err)
+			require.Equal(t, true, VerifyMerkleProof(root, hashes[i], uint32(i), path))
+			require.Equal(t, false, VerifyMerkleProof(root, hashes[i], uint32(len(hashes)+i), path))
+			if n > 1 {
+				require.Equal(t, false, VerifyMerkleProof(root, hashes[(i+1)%n], uint32(i), path))
+			}
+		}
+	}
+	_, err := MerkleProof(nil, 0)
+	require.Error(t, err)
+}
@@ -0,0 +1,64 @@
+package verifier
+
+import (
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/block"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/hash"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMMR(t *testing.T) {
+	m := NewMMR()
+	require.Equal(t, uint64(0), m.Leaves())
+	_, err := m.Prove(0)
+	require.Error(t, err)
+
+	leaves := make([]util.Uint256, 11)
+	for i := range leaves {
+		leaves[i] = hash.Sha256([]byte{byte(i)})
+		m.Append(leaves[i])
+		root := m.Root()
+		for j := 0; j <= i; j++ {
+			proof, err := m.Prove(uint64(j))
+			require.NoError(t, err)
+			require.Equal(t, true, VerifyMMRProof(root, leaves[j], proof))
+			if i > 0 {
+				require.Equal(t, false, VerifyMMRProof(root, leaves[(j+1)%(i+1)], proof))
+			}
+		}
+	}
+	// 11 leaves are 3 peaks of heights 3, 1 and 0
+	proof, err := m.Prove(9)
+	require.NoError(t, err)
+	require.Len(t, proof.Peaks, 3)
+	require.Len(t, proof.Siblings, 1)
+	require.Equal(t, hashMMRNode(leaves[8], leaves[9]), proof.Peaks[1])
+
+	// Tampered proofs
+	root := m.Root()
+	proof.Leaves = 10
+	require.Equal(t, false, VerifyMMRProof(root, leaves[9], proof))
+	proof.Leaves = 11
+	proof.Siblings[0] = leaves[7]
+	require.Equal(t, false, VerifyMMRProof(root, leaves[9], proof))
+	require.Equal(t, false, VerifyMMRProof(root, leaves[9], nil))
+}
+
+func TestLightClientHistory(t *testing.T) {
+	v := newTestValidators(t)
+	trusted := v.next(&block.Header{}, v.hash())
+	client := NewLightClient(trusted, testNetwork)
+	header := v.next(trusted, v.hash())
+	v.sign(header, 0, 1, 2, 3, 4)
+	require.NoError(t, client.Update(header))
+
+	root := client.History().Root()
+	proof, err := client.History().Prove(1)
+	require.NoError(t, err)
+	require.Equal(t, true, VerifyMMRProof(root, header.Hash(), proof))
+	proof, err = client.History().Prove(0)
+	require.NoError(t, err)
+	require.Equal(t, true, VerifyMMRProof(root, trusted.Hash(), proof))
+}
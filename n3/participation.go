@@ -0,0 +1,45 @@
+package verifier
+
+import (
+	"crypto/elliptic"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/block"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/hash"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+)
+
+// Signers returns the validators of a verified header from its verification script
+// and the indexes of the validators whose signatures are in its invocation script.
+func Signers(header *block.Header, network uint32) (keys.PublicKeys, []int, error) {
+	pubs, ok := parseVerificationScript(header.Script.VerificationScript)
+	if !ok {
+		return nil, nil, ErrScript
+	}
+	sigs, ok := parseInvocationScript(header.Script.InvocationScript)
+	if !ok {
+		return nil, nil, ErrScript
+	}
+	validators := make(keys.PublicKeys, len(pubs))
+	for i := range pubs {
+		key, err := keys.NewPublicKeyFromBytes(pubs[i], elliptic.P256())
+		if err != nil {
+			return nil, nil, err
+		}
+		validators[i] = key
+	}
+	// Signatures are in the order of keys, as CheckMultisig expects
+	msg := hash.NetSha256(network, header).BytesBE()
+	signers := make([]int, 0, len(sigs))
+	var vi int
+	for _, sig := range sigs {
+		for vi < len(validators) && !validators[vi].Verify(sig, msg) {
+			vi++
+		}
+		if vi == len(validators) {
+			return nil, nil, ErrSignature
+		}
+		signers = append(signers, vi)
+		vi++
+	}
+	return validators, signers, nil
+}
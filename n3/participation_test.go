@@ -0,0 +1,32 @@
+package verifier
+
+import (
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/block"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigners(t *testing.T) {
+	current := new(block.Header)
+	require.NoError(t, current.UnmarshalJSON([]byte(testCurrentHeader)))
+	validators, signers, err := Signers(current, 860833102)
+	require.NoError(t, err)
+	require.Equal(t, DefaultLimits.ValidatorsCount, len(validators))
+	require.Equal(t, 5, len(signers))
+	_, _, err = Signers(current, 894710606)
+	require.ErrorIs(t, err, ErrSignature)
+
+	v := newTestValidators(t)
+	trusted := &block.Header{Timestamp: 1628062127819, NextConsensus: v.hash()}
+	header := v.next(trusted, v.hash())
+	v.sign(header, 0, 2, 3, 5, 6)
+	validators, signers, err = Signers(header, testNetwork)
+	require.NoError(t, err)
+	require.Equal(t, []int{0, 2, 3, 5, 6}, signers)
+	require.Equal(t, v.privs[1].PublicKey(), validators[1])
+
+	header.Script.InvocationScript = header.Script.InvocationScript[1:]
+	_, _, err = Signers(header, testNetwork)
+	require.ErrorIs(t, err, ErrScript)
+}
@@ -0,0 +1,162 @@
+// Package rpctest provides an in-process N3 JSON-RPC server for tests.
+package rpctest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	"github.com/nspcc-dev/neo-go/pkg/util"
+)
+
+// Server is an in-process N3 JSON-RPC server replaying recorded verbose headers, it
+// answers getblockcount and getblockheader by index or hash, single or batched.
+type Server struct {
+	*httptest.Server
+
+	lock     sync.Mutex
+	byIndex  map[uint32]json.RawMessage
+	byHash   map[util.Uint256]json.RawMessage
+	count    uint32
+	failures int
+	requests int
+}
+
+// NewServer starts Server with recorded headers, it must be closed.
+func NewServer(headers []json.RawMessage) (*Server, error) {
+	s := &Server{
+		byIndex: make(map[uint32]json.RawMessage, len(headers)),
+		byHash:  make(map[util.Uint256]json.RawMessage, len(headers)),
+	}
+	for _, raw := range headers {
+		if err := s.add(raw); err != nil {
+			return nil, err
+		}
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s, nil
+}
+
+// AddHeader makes a recorded header available, e.g. to emulate a growing chain.
+func (s *Server) AddHeader(raw json.RawMessage) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.add(raw)
+}
+
+// FailNext makes the next n HTTP requests fail with 503.
+func (s *Server) FailNext(n int) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.failures = n
+}
+
+// Requests returns the number of HTTP requests served.
+func (s *Server) Requests() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.requests
+}
+
+func (s *Server) add(raw json.RawMessage) error {
+	header := new(blockHeaderJSON)
+	if err := json.Unmarshal(raw, header); err != nil {
+		return err
+	}
+	s.byIndex[header.Index] = raw
+	s.byHash[header.Hash] = raw
+	s.count = max(s.count, header.Index+1)
+	return nil
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type blockHeaderJSON struct {
+	Hash  util.Uint256 `json:"hash"`
+	Index uint32       `json:"index"`
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.requests++
+	if s.failures > 0 {
+		s.failures--
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	var (
+		raw   json.RawMessage
+		batch []json.RawMessage
+	)
+	if err := json.NewDecoder(r.Body).Decode(&raw); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	isBatch := len(raw) > 0 && raw[0] == '['
+	if isBatch {
+		if err := json.Unmarshal(raw, &batch); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	} else {
+		batch = []json.RawMessage{raw}
+	}
+	resps := make([]map[string]any, len(batch))
+	for i := range batch {
+		resps[i] = s.handle(batch[i])
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if isBatch {
+		json.NewEncoder(w).Encode(resps)
+	} else {
+		json.NewEncoder(w).Encode(resps[0])
+	}
+}
+
+func (s *Server) handle(raw json.RawMessage) map[string]any {
+	var req struct {
+		ID     json.RawMessage   `json:"id"`
+		Method string            `json:"method"`
+		Params []json.RawMessage `json:"params"`
+	}
+	resp := map[string]any{"jsonrpc": "2.0"}
+	if err := json.Unmarshal(raw, &req); err != nil {
+		resp["error"] = rpcError{Code: -32700, Message: err.Error()}
+		return resp
+	}
+	resp["id"] = req.ID
+	switch req.Method {
+	case "getblockcount":
+		resp["result"] = s.count
+	case "getblockheader":
+		if len(req.Params) < 1 {
+			resp["error"] = rpcError{Code: -32602, Message: "invalid params"}
+			return resp
+		}
+		var (
+			header json.RawMessage
+			ok     bool
+			index  uint32
+			hash   util.Uint256
+		)
+		if err := json.Unmarshal(req.Params[0], &index); err == nil {
+			header, ok = s.byIndex[index]
+		} else if err := json.Unmarshal(req.Params[0], &hash); err == nil {
+			header, ok = s.byHash[hash]
+		}
+		if !ok {
+			resp["error"] = rpcError{Code: -100, Message: "Unknown block"}
+			return resp
+		}
+		resp["result"] = header
+	default:
+		resp["error"] = rpcError{Code: -32601, Message: fmt.Sprintf("method not found: %s", req.Method)}
+	}
+	return resp
+}
@@ -0,0 +1,355 @@
+package verifier
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/block"
+)
+
+// HeaderSource provides headers of a N3 network.
+type HeaderSource interface {
+	// Height returns the index of the latest available header.
+	Height(ctx context.Context) (uint32, error)
+	// Headers returns count consecutive headers starting at index from.
+	Headers(ctx context.Context, from uint32, count int) ([]*block.Header, error)
+}
+
+// ErrHeaderNotFound is returned by sources for headers they don't have.
+var ErrHeaderNotFound = errors.New("header not found")
+
+// RPCSource is a HeaderSource backed by the getblockcount and getblockheader methods
+// of a N3 JSON-RPC node.
+type RPCSource struct {
+	Endpoint string
+	Client   *http.Client
+	// Retries is the number of retries of a request failed by transport or HTTP
+	// errors, RetryDelay is the delay before the first retry and doubles after it.
+	Retries    int
+	RetryDelay time.Duration
+	// BatchSize is the maximum number of calls in a batch request.
+	BatchSize int
+}
+
+// NewRPCSource returns RPCSource of endpoint with default settings.
+func NewRPCSource(endpoint string) *RPCSource {
+	return &RPCSource{
+		Endpoint:   endpoint,
+		Client:     http.DefaultClient,
+		Retries:    3,
+		RetryDelay: time.Second,
+		BatchSize:  100,
+	}
+}
+
+type rpcRequest struct {
+	JSONRPC string `json:"jsonrpc"`
+	ID      int    `json:"id"`
+	Method  string `json:"method"`
+	Params  []any  `json:"params"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *rpcError) Error() string {
+	return fmt.Sprintf("RPC error %d: %s", e.Code, e.Message)
+}
+
+type rpcResponse struct {
+	ID     json.RawMessage `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *rpcError       `json:"error"`
+}
+
+func (s *RPCSource) Height(ctx context.Context) (uint32, error) {
+	resps, err := s.call(ctx, []rpcRequest{{Method: "getblockcount", Params: []any{}}})
+	if err != nil {
+		return 0, err
+	}
+	if resps[0].Error != nil {
+		return 0, resps[0].Error
+	}
+	var count uint32
+	if err := json.Unmarshal(resps[0].Result, &count); err != nil {
+		return 0, err
+	}
+	if count == 0 {
+		return 0, errors.New("empty chain")
+	}
+	return count - 1, nil
+}
+
+func (s *RPCSource) Headers(ctx context.Context, from uint32, count int) ([]*block.Header, error) {
+	if count < 0 {
+		return nil, fmt.Errorf("negative count %d", count)
+	}
+	headers := make([]*block.Header, 0, count)
+	batchSize := max(s.BatchSize, 1)
+	for len(headers) < count {
+		reqs := make([]rpcRequest, min(batchSize, count-len(headers)))
+		for i := range reqs {
+			reqs[i] = rpcRequest{Method: "getblockheader", Params: []any{from + uint32(len(headers)+i), true}}
+		}
+		resps, err := s.call(ctx, reqs)
+		if err != nil {
+			return nil, err
+		}
+		for i := range resps {
+			index := from + uint32(len(headers))
+			if resps[i].Error != nil {
+				return nil, fmt.Errorf("header %d: %w", index, resps[i].Error)
+			}
+			if len(resps[i].Result) == 0 || string(resps[i].Result) == "null" {
+				return nil, fmt.Errorf("header %d: %w", index, ErrHeaderNotFound)
+			}
+			header := new(block.Header)
+			if err := header.UnmarshalJSON(resps[i].Result); err != nil {
+				return nil, fmt.Errorf("header %d: %w", index, err)
+			}
+			if header.Index != index {
+				return nil, fmt.Errorf("header %d: unexpected index %d", index, header.Index)
+			}
+			headers = append(headers, header)
+		}
+	}
+	return headers, nil
+}
+
+// call sends reqs as a batch with retries and returns responses in request order.
+func (s *RPCSource) call(ctx context.Context, reqs []rpcRequest) ([]rpcResponse, error) {
+	for i := range reqs {
+		reqs[i].JSONRPC = "2.0"
+		reqs[i].ID = i + 1
+	}
+	body, err := json.Marshal(reqs)
+	if err != nil {
+		return nil, err
+	}
+	delay := s.RetryDelay
+	for attempt := 0; ; attempt++ {
+		resps, err := s.post(ctx, body, len(reqs))
+		if err == nil || attempt >= s.Retries || ctx.Err() != nil {
+			return resps, err
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+func (s *RPCSource) post(ctx context.Context, body []byte, n int) ([]rpcResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected HTTP status %s", resp.Status)
+	}
+	var batch []rpcResponse
+	if err := json.NewDecoder(resp.Body).Decode(&batch); err != nil {
+		return nil, err
+	}
+	ordered := make([]rpcResponse, n)
+	for _, r := range batch {
+		var id int
+		if err := json.Unmarshal(r.ID, &id); err != nil || id < 1 || id > n {
+			return nil, fmt.Errorf("unexpected response id %s", r.ID)
+		}
+		ordered[id-1] = r
+	}
+	for i := range ordered {
+		if ordered[i].ID == nil {
+			return nil, fmt.Errorf("missing response %d", i+1)
+		}
+	}
+	return ordered, nil
+}
+
+// FileSource is a HeaderSource of headers recorded in files.
+type FileSource struct {
+	headers map[uint32]*block.Header
+	tail    uint32
+	height  uint32
+}
+
+// NewFileSource loads verbose headers from JSON array or newline-delimited JSON files,
+// JSON-RPC responses are unwrapped. Directories in paths are read file by file and "-"
+// is stdin.
+func NewFileSource(paths ...string) (*FileSource, error) {
+	s := &FileSource{headers: make(map[uint32]*block.Header)}
+	for _, path := range paths {
+		if path == "-" {
+			if err := s.load(os.Stdin); err != nil {
+				return nil, fmt.Errorf("stdin: %w", err)
+			}
+			continue
+		}
+		files, err := recordedFiles(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			if err := s.loadFile(file); err != nil {
+				return nil, fmt.Errorf("%s: %w", file, err)
+			}
+		}
+	}
+	if len(s.headers) == 0 {
+		return nil, errors.New("no headers")
+	}
+	return s, nil
+}
+
+func (s *FileSource) loadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return s.load(f)
+}
+
+func (s *FileSource) load(r io.Reader) error {
+	raws, err := readRecordedHeaders(r)
+	if err != nil {
+		return err
+	}
+	for _, raw := range raws {
+		header := new(block.Header)
+		if err := header.UnmarshalJSON(raw); err != nil {
+			return err
+		}
+		if len(s.headers) == 0 {
+			s.tail, s.height = header.Index, header.Index
+		}
+		if known, ok := s.headers[header.Index]; ok && known.Hash() != header.Hash() {
+			return fmt.Errorf("header %d: conflicting hashes", header.Index)
+		}
+		s.headers[header.Index] = header
+		s.tail = min(s.tail, header.Index)
+		s.height = max(s.height, header.Index)
+	}
+	return nil
+}
+
+// Tail returns the index of the earliest header.
+func (s *FileSource) Tail(ctx context.Context) (uint32, error) {
+	return s.tail, nil
+}
+
+func (s *FileSource) Height(ctx context.Context) (uint32, error) {
+	return s.height, nil
+}
+
+func (s *FileSource) Headers(ctx context.Context, from uint32, count int) ([]*block.Header, error) {
+	if count < 0 {
+		return nil, fmt.Errorf("negative count %d", count)
+	}
+	headers := make([]*block.Header, count)
+	for i := range headers {
+		header, ok := s.headers[from+uint32(i)]
+		if !ok {
+			return nil, fmt.Errorf("header %d: %w", from+uint32(i), ErrHeaderNotFound)
+		}
+		headers[i] = header
+	}
+	return headers, nil
+}
+
+// recordedFiles returns path or the files of directory path.
+func recordedFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			files = append(files, filepath.Join(path, entry.Name()))
+		}
+	}
+	return files, nil
+}
+
+// readRecordedHeaders decodes a JSON array or a stream of JSON values, unwrapping
+// JSON-RPC responses.
+func readRecordedHeaders(r io.Reader) ([]json.RawMessage, error) {
+	br := bufio.NewReader(r)
+	var values []json.RawMessage
+	dec := json.NewDecoder(br)
+	for {
+		b, err := br.Peek(1)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil, errors.New("empty input")
+			}
+			return nil, err
+		}
+		if !bytes.ContainsAny(b, " \t\r\n") {
+			break
+		}
+		br.ReadByte()
+	}
+	if b, _ := br.Peek(1); b[0] == '[' {
+		if err := dec.Decode(&values); err != nil {
+			return nil, err
+		}
+	} else {
+		for {
+			var value json.RawMessage
+			err := dec.Decode(&value)
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+		}
+	}
+	for i := range values {
+		var resp rpcResponse
+		if err := json.Unmarshal(values[i], &resp); err != nil {
+			return nil, err
+		}
+		if resp.Error != nil {
+			return nil, resp.Error
+		}
+		if resp.Result != nil {
+			values[i] = resp.Result
+		}
+	}
+	return values, nil
+}
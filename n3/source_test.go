@@ -0,0 +1,134 @@
+package verifier
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/block"
+	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/stretchr/testify/require"
+	"github.com/txhsl/n3-dbft-verifier/rpctest"
+)
+
+func newTestRPCServer(t testing.TB) *rpctest.Server {
+	s, err := rpctest.NewServer([]json.RawMessage{json.RawMessage(testParentHeader), json.RawMessage(testCurrentHeader)})
+	require.NoError(t, err)
+	t.Cleanup(s.Close)
+	return s
+}
+
+func TestRPCSource(t *testing.T) {
+	s := newTestRPCServer(t)
+	src := NewRPCSource(s.URL)
+	src.RetryDelay = time.Millisecond
+	ctx := context.Background()
+
+	height, err := src.Height(ctx)
+	require.NoError(t, err)
+	require.Equal(t, uint32(10000), height)
+	headers, err := src.Headers(ctx, 9999, 2)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(headers))
+	require.NoError(t, CheckUpdateHeader(headers[0], headers[1], 860833102))
+
+	// Batching
+	requests := s.Requests()
+	src.BatchSize = 1
+	_, err = src.Headers(ctx, 9999, 2)
+	require.NoError(t, err)
+	require.Equal(t, requests+2, s.Requests())
+
+	// Retries
+	s.FailNext(2)
+	_, err = src.Height(ctx)
+	require.NoError(t, err)
+	s.FailNext(4)
+	_, err = src.Height(ctx)
+	require.Error(t, err)
+
+	// Cancellation
+	s.FailNext(1)
+	src.RetryDelay = time.Hour
+	cctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	_, err = src.Height(cctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	_, err = src.Headers(ctx, 10000, 2)
+	require.Error(t, err)
+	_, err = src.Headers(ctx, 10000, -1)
+	require.Error(t, err)
+
+	// Responses with ids other than the request ones
+	bad := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"jsonrpc":"2.0","id":null,"error":{"code":-32700,"message":"parse error"}}]`))
+	}))
+	defer bad.Close()
+	_, err = NewRPCSource(bad.URL).Height(ctx)
+	require.ErrorContains(t, err, "unexpected response id null")
+}
+
+func TestFileSource(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "headers.ndjson")
+	data := `{"jsonrpc":"2.0","id":1,"result":` + testParentHeader + "}\n" + testCurrentHeader + "\n"
+	require.NoError(t, os.WriteFile(path, []byte(data), 0o644))
+	src, err := NewFileSource(path)
+	require.NoError(t, err)
+	height, err := src.Height(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, uint32(10000), height)
+	headers, err := src.Headers(context.Background(), 9999, 2)
+	require.NoError(t, err)
+	require.NoError(t, CheckUpdateHeader(headers[0], headers[1], 860833102))
+	_, err = src.Headers(context.Background(), 9999, 3)
+	require.ErrorIs(t, err, ErrHeaderNotFound)
+	_, err = src.Headers(context.Background(), 9999, -1)
+	require.Error(t, err)
+
+	// Directory and stdin
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "parent.json"), []byte(testParentHeader), 0o644))
+	stdin := filepath.Join(t.TempDir(), "stdin")
+	require.NoError(t, os.WriteFile(stdin, []byte(testCurrentHeader), 0o644))
+	f, err := os.Open(stdin)
+	require.NoError(t, err)
+	defer f.Close()
+	defer func(stdin *os.File) { os.Stdin = stdin }(os.Stdin)
+	os.Stdin = f
+	src, err = NewFileSource(dir, "-")
+	require.NoError(t, err)
+	tail, err := src.Tail(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, uint32(9999), tail)
+	headers, err = src.Headers(context.Background(), 9999, 2)
+	require.NoError(t, err)
+	require.NoError(t, CheckUpdateHeader(headers[0], headers[1], 860833102))
+
+	require.NoError(t, os.WriteFile(path, []byte("["+testParentHeader+","+testCurrentHeader+"]"), 0o644))
+	_, err = NewFileSource(path)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, []byte("["+testParentHeader+","+testParentHeader+"]"), 0o644))
+	_, err = NewFileSource(path)
+	require.NoError(t, err)
+	parent := new(block.Header)
+	require.NoError(t, parent.UnmarshalJSON([]byte(testParentHeader)))
+	parent.Nonce++
+	w := io.NewBufBinWriter()
+	parent.EncodeBinary(w.BinWriter)
+	conflicting := new(block.Header)
+	conflicting.DecodeBinary(io.NewBinReaderFromBuf(w.Bytes()))
+	raw, err := conflicting.MarshalJSON()
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, []byte("["+testParentHeader+","+string(raw)+"]"), 0o644))
+	_, err = NewFileSource(path)
+	require.ErrorContains(t, err, "header 9999: conflicting hashes")
+	require.NoError(t, os.WriteFile(path, []byte(" "), 0o644))
+	_, err = NewFileSource(path)
+	require.Error(t, err)
+}
@@ -3,6 +3,7 @@ package verifier
 import (
 	"crypto/elliptic"
 	"encoding/binary"
+	"errors"
 
 	"github.com/nspcc-dev/neo-go/pkg/core/block"
 	"github.com/nspcc-dev/neo-go/pkg/core/interop/interopnames"
@@ -18,71 +19,110 @@ const (
 	SignatureDataLen = SignatureLen + 2 // Length of signature data in script (PUSHDATA1 + signature length + signature).
 )
 
+var (
+	ErrPrevHash      = errors.New("previous hash mismatch")
+	ErrIndex         = errors.New("unexpected index")
+	ErrTimestamp     = errors.New("timestamp is not increasing")
+	ErrNextConsensus = errors.New("witness doesn't match next consensus")
+	ErrScript        = errors.New("malformed witness script")
+	ErrSignature     = errors.New("invalid multi-signature")
+)
+
 func VerifyUpdateHeader(parent, current *block.Header, network uint32) bool {
+	return CheckUpdateHeader(parent, current, network) == nil
+}
+
+// CheckUpdateHeader is VerifyUpdateHeader returning the reason of the failure.
+func CheckUpdateHeader(parent, current *block.Header, network uint32) error {
 	if current.PrevHash != parent.Hash() {
-		return false
+		return ErrPrevHash
 	}
 	if current.Index != parent.Index+1 {
-		return false
+		return ErrIndex
 	}
 	if current.Timestamp <= parent.Timestamp {
-		return false
+		return ErrTimestamp
 	}
 	// Format verification
 	expectedConsensus := parent.NextConsensus
 	exactConsensus := current.Script
 	if exactConsensus.ScriptHash() != expectedConsensus {
-		return false
+		return ErrNextConsensus
 	}
 	if len(exactConsensus.VerificationScript) < 7*PublicKeyDataLen+7 {
-		return false
+		return ErrScript
 	}
 	if len(exactConsensus.InvocationScript) < 5*SignatureDataLen {
-		return false
+		return ErrScript
 	}
 	// Content verification
+	pubs, ok := parseVerificationScript(exactConsensus.VerificationScript)
+	if !ok {
+		return ErrScript
+	}
+	sigs, ok := parseInvocationScript(exactConsensus.InvocationScript)
+	if !ok {
+		return ErrScript
+	}
+	// Check multi-sigs
+	if !vm.CheckMultisigPar(elliptic.P256(), hash.NetSha256(network, current).BytesBE(), pubs, sigs) {
+		return ErrSignature
+	}
+	return nil
+}
+
+func parseInvocationScript(script []byte) ([][]byte, bool) {
+	if len(script) < 5*SignatureDataLen {
+		return nil, false
+	}
+	// Invocation script, need to analyze the script outside
+	// Ref https://github.com/nspcc-dev/neo-go/blob/1436de45bfbe44b5e60710dafb117b647adddb24/internal/testchain/address.go#L129
+	sigs := make([][]byte, 5)
+	for i := range 5 {
+		if script[i*SignatureDataLen] != byte(opcode.PUSHDATA1) {
+			return nil, false
+		}
+		// Sig length
+		if script[i*SignatureDataLen+1] != byte(SignatureLen) {
+			return nil, false
+		}
+		// Sig data
+		sigs[i] = script[i*SignatureDataLen+2 : (i+1)*SignatureDataLen]
+	}
+	return sigs, true
+}
+
+func parseVerificationScript(script []byte) ([][]byte, bool) {
+	if len(script) < 7*PublicKeyDataLen+7 {
+		return nil, false
+	}
 	// Verification script, need to analyze the script outside
 	// Ref https://github.com/nspcc-dev/neo-go/blob/1436de45bfbe44b5e60710dafb117b647adddb24/pkg/smartcontract/contract.go#L16
-	if exactConsensus.VerificationScript[0] != byte(opcode.PUSH5) {
-		return false
+	if script[0] != byte(opcode.PUSH5) {
+		return nil, false
 	}
 	pubs := make([][]byte, 7)
 	for i := range 7 {
-		if exactConsensus.VerificationScript[i*PublicKeyDataLen+1] != byte(opcode.PUSHDATA1) {
-			return false
+		if script[i*PublicKeyDataLen+1] != byte(opcode.PUSHDATA1) {
+			return nil, false
 		}
 		// Key length
-		if exactConsensus.VerificationScript[i*PublicKeyDataLen+2] != byte(PublickeyLen) {
-			return false
+		if script[i*PublicKeyDataLen+2] != byte(PublickeyLen) {
+			return nil, false
 		}
 		// Key data
-		pubs[i] = exactConsensus.VerificationScript[i*PublicKeyDataLen+3 : (i+1)*PublicKeyDataLen+1]
+		pubs[i] = script[i*PublicKeyDataLen+3 : (i+1)*PublicKeyDataLen+1]
 	}
 	// Check the exact pubkey array length
-	if exactConsensus.VerificationScript[7*PublicKeyDataLen+1] != byte(opcode.PUSH7) {
-		return false
+	if script[7*PublicKeyDataLen+1] != byte(opcode.PUSH7) {
+		return nil, false
 	}
 	// Check the syscall
-	if exactConsensus.VerificationScript[7*PublicKeyDataLen+2] != byte(opcode.SYSCALL) {
-		return false
+	if script[7*PublicKeyDataLen+2] != byte(opcode.SYSCALL) {
+		return nil, false
 	}
-	if binary.LittleEndian.Uint32(exactConsensus.VerificationScript[7*PublicKeyDataLen+3:7*PublicKeyDataLen+7]) != interopnames.ToID([]byte(interopnames.SystemCryptoCheckMultisig)) {
-		return false
-	}
-	// Invocation script, need to analyze the script outside
-	// Ref https://github.com/nspcc-dev/neo-go/blob/1436de45bfbe44b5e60710dafb117b647adddb24/internal/testchain/address.go#L129
-	sigs := make([][]byte, 5)
-	for i := range 5 {
-		if exactConsensus.InvocationScript[i*SignatureDataLen] != byte(opcode.PUSHDATA1) {
-			return false
-		}
-		// Sig length
-		if exactConsensus.InvocationScript[i*SignatureDataLen+1] != byte(SignatureLen) {
-			return false
-		}
-		// Sig data
-		sigs[i] = exactConsensus.InvocationScript[i*SignatureDataLen+2 : (i+1)*SignatureDataLen]
+	if binary.LittleEndian.Uint32(script[7*PublicKeyDataLen+3:7*PublicKeyDataLen+7]) != interopnames.ToID([]byte(interopnames.SystemCryptoCheckMultisig)) {
+		return nil, false
 	}
-	// Check multi-sigs
-	return vm.CheckMultisigPar(elliptic.P256(), hash.NetSha256(network, current).BytesBE(), pubs, sigs)
+	return pubs, true
 }
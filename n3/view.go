@@ -0,0 +1,32 @@
+package verifier
+
+import (
+	"errors"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/block"
+	"github.com/nspcc-dev/neo-go/pkg/vm"
+)
+
+// ErrPrimaryIndex is returned for headers with PrimaryIndex out of the validator set.
+var ErrPrimaryIndex = errors.New("primary index out of validator set")
+
+// Primary returns the index of the primary validator of view at height in a set of n
+// validators, dBFT rotates it backwards with views starting from height mod n.
+func Primary(height uint32, view, n int) int {
+	return ((int(height%uint32(n))-view)%n + n) % n
+}
+
+// View returns the view a header was accepted in, inferred from its PrimaryIndex in
+// the validator set of its verification script. It's known modulo the validators count.
+func View(header *block.Header) (int, error) {
+	_, pubs, ok := vm.ParseMultiSigContract(header.Script.VerificationScript)
+	if !ok {
+		return 0, ErrScript
+	}
+	n := len(pubs)
+	if int(header.PrimaryIndex) >= n {
+		return 0, ErrPrimaryIndex
+	}
+	// primary = (height - view) mod n
+	return ((int(header.Index%uint32(n))-int(header.PrimaryIndex))%n + n) % n, nil
+}
@@ -0,0 +1,54 @@
+package verifier
+
+import (
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/block"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/stretchr/testify/require"
+)
+
+func TestView(t *testing.T) {
+	current := new(block.Header)
+	require.NoError(t, current.UnmarshalJSON([]byte(testCurrentHeader)))
+	view, err := View(current)
+	require.NoError(t, err)
+	require.Equal(t, 0, view)
+	require.Equal(t, int(current.PrimaryIndex), Primary(current.Index, 0, DefaultLimits.ValidatorsCount))
+
+	header := &block.Header{Index: 15, Script: current.Script}
+	for view := range DefaultLimits.ValidatorsCount {
+		header.PrimaryIndex = byte(Primary(header.Index, view, DefaultLimits.ValidatorsCount))
+		v, err := View(header)
+		require.NoError(t, err)
+		require.Equal(t, view, v)
+	}
+	require.Equal(t, 0, Primary(15, 1, 7))
+	require.Equal(t, 6, Primary(15, 2, 7))
+
+	header.PrimaryIndex = byte(DefaultLimits.ValidatorsCount)
+	_, err = View(header)
+	require.ErrorIs(t, err, ErrPrimaryIndex)
+
+	// Validator set of 4
+	pubs := make(keys.PublicKeys, 4)
+	sigs := make([][]byte, len(pubs))
+	for i := range pubs {
+		priv, err := keys.NewPrivateKey()
+		require.NoError(t, err)
+		pubs[i], sigs[i] = priv.PublicKey(), make([]byte, SignatureLen)
+	}
+	header.Script, _, err = BuildWitness(pubs, sigs)
+	require.NoError(t, err)
+	header.PrimaryIndex = byte(Primary(header.Index, 1, 4))
+	view, err = View(header)
+	require.NoError(t, err)
+	require.Equal(t, 1, view)
+	header.PrimaryIndex = 4
+	_, err = View(header)
+	require.ErrorIs(t, err, ErrPrimaryIndex)
+
+	header.Script.VerificationScript = nil
+	_, err = View(header)
+	require.ErrorIs(t, err, ErrScript)
+}
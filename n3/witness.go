@@ -0,0 +1,56 @@
+package verifier
+
+import (
+	"errors"
+	"slices"
+	"sort"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/hash"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+)
+
+// BuildWitness builds the header witness in the layout checked by VerifyUpdateHeader
+// from the signatures of some validators, sigs[i] is the signature of validators[i]
+// or nil. It returns the witness and its script hash, i.e. the NextConsensus of the
+// parent header.
+func BuildWitness(validators keys.PublicKeys, sigs [][]byte) (transaction.Witness, util.Uint160, error) {
+	if len(validators) == 0 || len(validators) > maxValidatorsCount || len(sigs) != len(validators) {
+		return transaction.Witness{}, util.Uint160{}, errors.New("unexpected number of validators")
+	}
+	// Order signatures as keys in the script
+	order := make([]int, len(validators))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool {
+		return validators[order[i]].Cmp(validators[order[j]]) < 0
+	})
+	m := len(validators) - (len(validators)-1)/3
+	ordered := make([][]byte, 0, m)
+	for _, i := range order {
+		if sigs[i] == nil {
+			continue
+		}
+		if len(sigs[i]) != SignatureLen {
+			return transaction.Witness{}, util.Uint160{}, errors.New("malformed signature")
+		}
+		if len(ordered) < m {
+			ordered = append(ordered, sigs[i])
+		}
+	}
+	if len(ordered) < m {
+		return transaction.Witness{}, util.Uint160{}, errors.New("not enough signatures")
+	}
+	// Keys are sorted in place
+	script, err := smartcontract.CreateMultiSigRedeemScript(m, slices.Clone(validators))
+	if err != nil {
+		return transaction.Witness{}, util.Uint160{}, err
+	}
+	return transaction.Witness{
+		InvocationScript:   buildInvocationScript(ordered),
+		VerificationScript: script,
+	}, hash.Hash160(script), nil
+}
@@ -0,0 +1,39 @@
+package verifier
+
+import (
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/block"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildWitness(t *testing.T) {
+	v := newTestValidators(t)
+	// Unsorted keys
+	privs := []*keys.PrivateKey{v.privs[3], v.privs[0], v.privs[6], v.privs[2], v.privs[5], v.privs[1], v.privs[4]}
+	validators := make(keys.PublicKeys, len(privs))
+	for i := range privs {
+		validators[i] = privs[i].PublicKey()
+	}
+	parent := v.next(&block.Header{}, v.hash())
+	header := v.next(parent, v.hash())
+	sigs := make([][]byte, len(privs))
+	for _, i := range []int{0, 2, 3, 4} {
+		sigs[i] = privs[i].SignHashable(testNetwork, header)
+	}
+	_, _, err := BuildWitness(validators, sigs)
+	require.Error(t, err)
+
+	sigs[6] = privs[6].SignHashable(testNetwork, header)
+	witness, nextConsensus, err := BuildWitness(validators, sigs)
+	require.NoError(t, err)
+	require.Equal(t, v.hash(), nextConsensus)
+	require.Equal(t, v.script, witness.VerificationScript)
+	require.Equal(t, v.privs[3].PublicKey().Bytes(), validators[0].Bytes())
+	header.Script = witness
+	require.Equal(t, true, VerifyUpdateHeader(parent, header, testNetwork))
+
+	_, _, err = BuildWitness(validators[1:], sigs[1:])
+	require.Error(t, err)
+}
@@ -0,0 +1,30 @@
+package verifier
+
+import (
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// VerifyBlockBody checks that body is the one committed to by a verified header, so a
+// full block from an untrusted peer can be accepted once its header is verified.
+func VerifyBlockBody(header *types.Header, body *types.Body) bool {
+	if header.GasUsed > header.GasLimit {
+		return false
+	}
+	// Check txs
+	if types.DeriveSha(types.Transactions(body.Transactions), trie.NewStackTrie(nil)) != header.TxHash {
+		return false
+	}
+	// Check uncles, dBFT never produces them
+	if len(body.Uncles) != 0 || header.UncleHash != types.EmptyUncleHash {
+		return false
+	}
+	// Check withdrawals
+	if header.WithdrawalsHash == nil {
+		return body.Withdrawals == nil
+	}
+	if body.Withdrawals == nil {
+		return false
+	}
+	return types.DeriveSha(types.Withdrawals(body.Withdrawals), trie.NewStackTrie(nil)) == *header.WithdrawalsHash
+}
@@ -0,0 +1,41 @@
+package verifier
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyBlockBody(t *testing.T) {
+	to := common.HexToAddress("0x1212000000000000000000000000000000000004")
+	tx := types.NewTx(&types.LegacyTx{Nonce: 1, GasPrice: big.NewInt(20000000000), Gas: 21000, To: &to, Value: big.NewInt(1)})
+	header := &types.Header{
+		UncleHash:       types.EmptyUncleHash,
+		TxHash:          common.HexToHash("0x0b8a2aae2ab7a1dc1d3fb4ae2cd7ba72c1e3fbd3d64d5a34b6d3d0a31bce3b8e"),
+		GasLimit:        30000000,
+		GasUsed:         21000,
+		WithdrawalsHash: &types.EmptyWithdrawalsHash,
+	}
+	body := &types.Body{Transactions: []*types.Transaction{tx}, Withdrawals: []*types.Withdrawal{}}
+	require.Equal(t, false, VerifyBlockBody(header, body))
+
+	header.TxHash = types.DeriveSha(types.Transactions(body.Transactions), trie.NewStackTrie(nil))
+	require.Equal(t, true, VerifyBlockBody(header, body))
+
+	// Empty block
+	require.Equal(t, false, VerifyBlockBody(header, &types.Body{Withdrawals: []*types.Withdrawal{}}))
+	header.TxHash = types.EmptyTxsHash
+	require.Equal(t, true, VerifyBlockBody(header, &types.Body{Withdrawals: []*types.Withdrawal{}}))
+	require.Equal(t, false, VerifyBlockBody(header, &types.Body{}))
+
+	// Uncles
+	require.Equal(t, false, VerifyBlockBody(header, &types.Body{Uncles: []*types.Header{header}, Withdrawals: []*types.Withdrawal{}}))
+
+	// Gas
+	header.GasUsed = header.GasLimit + 1
+	require.Equal(t, false, VerifyBlockBody(header, &types.Body{Withdrawals: []*types.Withdrawal{}}))
+}
@@ -0,0 +1,52 @@
+package verifier
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// BloomMatches reports whether the logs bloom of a verified header may include a log
+// emitted by address with the given topics. Topics follow the eth_getLogs filter
+// layout: every position holds a set of alternatives and an empty set matches any
+// topic. A false result is definitive, a true one still needs the receipts.
+func BloomMatches(header *types.Header, address common.Address, topics [][]common.Hash) bool {
+	if !types.BloomLookup(header.Bloom, address) {
+		return false
+	}
+	for _, alternatives := range topics {
+		if len(alternatives) == 0 {
+			continue
+		}
+		var match bool
+		for _, topic := range alternatives {
+			if types.BloomLookup(header.Bloom, topic) {
+				match = true
+				break
+			}
+		}
+		if !match {
+			return false
+		}
+	}
+	return true
+}
+
+// BloomCandidates verifies headers as a chain on top of the trusted one with
+// CheckUpdateHeader and returns the heights whose blooms match address and topics, i.e.
+// the only blocks whose receipts have to be fetched and proven.
+func BloomCandidates(config *ChainConfig, trusted *types.Header, headers []*types.Header, address common.Address, topics [][]common.Hash) ([]uint64, error) {
+	var heights []uint64
+	parent := trusted
+	for _, current := range headers {
+		if err := CheckUpdateHeader(config, parent, current); err != nil {
+			return nil, fmt.Errorf("header %d: %w", current.Number, err)
+		}
+		if BloomMatches(current, address, topics) {
+			heights = append(heights, current.Number.Uint64())
+		}
+		parent = current
+	}
+	return heights, nil
+}
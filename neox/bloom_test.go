@@ -0,0 +1,62 @@
+package verifier
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBloomMatches(t *testing.T) {
+	address := common.HexToAddress("0x1212000000000000000000000000000000000004")
+	transfer := common.HexToHash("0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef")
+	approval := common.HexToHash("0x8c5be1e5ebec7d5bd14f71427d1e84f3dd0314c0f7b2291e5b200ac8c7c3b925")
+	header := &types.Header{Number: big.NewInt(1)}
+	header.Bloom.Add(address.Bytes())
+	header.Bloom.Add(transfer.Bytes())
+
+	require.Equal(t, true, BloomMatches(header, address, nil))
+	require.Equal(t, true, BloomMatches(header, address, [][]common.Hash{{transfer}}))
+	require.Equal(t, true, BloomMatches(header, address, [][]common.Hash{{approval, transfer}, {}}))
+	require.Equal(t, false, BloomMatches(header, address, [][]common.Hash{{approval}}))
+	require.Equal(t, false, BloomMatches(header, common.HexToAddress("0x1212000000000000000000000000000000000005"), nil))
+
+	_, err := BloomCandidates(testConfig, &types.Header{Number: big.NewInt(0)}, []*types.Header{header}, address, nil)
+	require.ErrorIs(t, err, ErrParentHash)
+}
+
+func TestBloomCandidates(t *testing.T) {
+	address := common.HexToAddress("0x1212000000000000000000000000000000000004")
+	transfer := common.HexToHash("0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef")
+	approval := common.HexToHash("0x8c5be1e5ebec7d5bd14f71427d1e84f3dd0314c0f7b2291e5b200ac8c7c3b925")
+	v := newTestValidators(t)
+	trusted := newTestGenesis(v.commitment())
+	var headers []*types.Header
+	parent := trusted
+	for i := range 4 {
+		header := nextTestHeader(parent, v.commitment())
+		if i%2 == 1 {
+			header.Bloom.Add(address.Bytes())
+			header.Bloom.Add(transfer.Bytes())
+		}
+		v.seal(t, header, ExtraV2, ExtraV1ECDSAScheme, 0, 1, 2, 3, 4)
+		headers = append(headers, header)
+		parent = header
+	}
+
+	heights, err := BloomCandidates(testConfig, trusted, headers, address, [][]common.Hash{{transfer}})
+	require.NoError(t, err)
+	require.Equal(t, []uint64{2, 4}, heights)
+	heights, err = BloomCandidates(testConfig, trusted, headers, address, [][]common.Hash{{approval}})
+	require.NoError(t, err)
+	require.Empty(t, heights)
+	heights, err = BloomCandidates(testConfig, trusted, headers, common.HexToAddress("0x1212000000000000000000000000000000000005"), nil)
+	require.NoError(t, err)
+	require.Empty(t, heights)
+
+	// Broken chain
+	_, err = BloomCandidates(testConfig, trusted, headers[1:], address, nil)
+	require.ErrorIs(t, err, ErrParentHash)
+}
@@ -0,0 +1,165 @@
+package verifier
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ValidatorSetChange is emitted when a verified header changes MixDigest, the
+// commitment to the next consensus.
+type ValidatorSetChange struct {
+	Height uint64
+	Old    common.Hash
+	New    common.Hash
+	// Addresses or GlobalKey of the old set that signed the change, depending on
+	// the extra scheme of the header.
+	Addresses []common.Address
+	GlobalKey []byte
+}
+
+// Verification is emitted for every header verified by Update, Err is the
+// CheckUpdateHeader error of a rejected header.
+type Verification struct {
+	Header   *types.Header
+	Duration time.Duration
+	Err      error
+}
+
+// LightClient follows the chain from a trusted header, accepting only headers that
+// pass VerifyUpdateHeaderWithConfig.
+type LightClient struct {
+	lock    sync.RWMutex
+	config  *ChainConfig
+	head    *types.Header
+	history *MMR
+
+	// notify is held while delivering events, it's taken before releasing lock so
+	// events are delivered in the order of updates.
+	notify               sync.Mutex
+	onValidatorSetChange []func(ValidatorSetChange)
+	onVerification       []func(Verification)
+}
+
+func NewLightClient(config *ChainConfig, trusted *types.Header) *LightClient {
+	history := NewMMR()
+	history.Append(trusted.Hash())
+	return &LightClient{
+		config:  config,
+		head:    trusted,
+		history: history,
+	}
+}
+
+// ResumeLightClient returns a light client following a verified head, history is the
+// accumulator of the verified headers up to head, e.g. rebuilt from a header store.
+func ResumeLightClient(config *ChainConfig, head *types.Header, history *MMR) *LightClient {
+	return &LightClient{
+		config:  config,
+		head:    head,
+		history: history,
+	}
+}
+
+// Config returns the fork schedule headers are verified with.
+func (c *LightClient) Config() *ChainConfig {
+	return c.config
+}
+
+// Head returns the latest verified header.
+func (c *LightClient) Head() *types.Header {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.head
+}
+
+// History returns the accumulator of verified header hashes, its i-th leaf is the
+// header at the trusted height plus i.
+func (c *LightClient) History() *MMR {
+	return c.history
+}
+
+// OnValidatorSetChange registers f to be called on every validator set transition.
+// Callbacks run synchronously in Update in the order of updates, they must not call
+// Update.
+func (c *LightClient) OnValidatorSetChange(f func(ValidatorSetChange)) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.onValidatorSetChange = append(c.onValidatorSetChange, f)
+}
+
+// OnVerification registers f to be called on every verified or rejected header,
+// headers failing to commit are not reported. Callbacks run synchronously in Update
+// in the order of updates, they must not call Update.
+func (c *LightClient) OnVerification(f func(Verification)) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.onVerification = append(c.onVerification, f)
+}
+
+// Update verifies header against the head and makes it the new head on success, it
+// returns the CheckUpdateHeader error otherwise.
+func (c *LightClient) Update(header *types.Header) error {
+	return c.UpdateWith(header, nil)
+}
+
+// UpdateWith is Update calling commit once header is verified and before it becomes
+// the head, e.g. to persist it. The head isn't changed if commit fails, its error is
+// returned then.
+func (c *LightClient) UpdateWith(header *types.Header, commit func() error) error {
+	c.lock.Lock()
+	parent := c.head
+	start := time.Now()
+	err := CheckUpdateHeader(c.config, parent, header)
+	verification := Verification{Header: header, Duration: time.Since(start), Err: err}
+	if err == nil && commit != nil {
+		if err := commit(); err != nil {
+			c.lock.Unlock()
+			return err
+		}
+	}
+	if err == nil {
+		c.head = header
+		c.history.Append(header.Hash())
+	}
+	callbacks, verified := c.onValidatorSetChange, c.onVerification
+	c.notify.Lock()
+	defer c.notify.Unlock()
+	c.lock.Unlock()
+
+	for _, f := range verified {
+		f(verification)
+	}
+	if err != nil {
+		return err
+	}
+
+	if header.MixDigest != parent.MixDigest {
+		event := ValidatorSetChange{
+			Height: header.Number.Uint64(),
+			Old:    parent.MixDigest,
+			New:    header.MixDigest,
+		}
+		event.Addresses, event.GlobalKey = consensusOf(header.Extra)
+		for _, f := range callbacks {
+			f(event)
+		}
+	}
+	return nil
+}
+
+// consensusOf returns the signers of a verified extra, either the addresses or the
+// global public key.
+func consensusOf(extra []byte) ([]common.Address, []byte) {
+	e, err := ParseExtra(extra)
+	if err != nil {
+		return nil, nil
+	}
+	if e.GlobalKey != nil {
+		pub := e.GlobalKey.Bytes()
+		return nil, pub[:]
+	}
+	return e.Addresses, nil
+}
@@ -0,0 +1,63 @@
+package verifier
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func decodeTestHeader(t testing.TB, data string) *types.Header {
+	header := new(types.Header)
+	require.NoError(t, header.UnmarshalJSON([]byte(data)))
+	return header
+}
+
+func TestLightClient(t *testing.T) {
+	trusted := decodeTestHeader(t, testV0ToV1Parent)
+	client := NewLightClient(DefaultChainConfig, trusted)
+	var events []ValidatorSetChange
+	client.OnValidatorSetChange(func(e ValidatorSetChange) {
+		events = append(events, e)
+	})
+	var verifications []Verification
+	client.OnVerification(func(v Verification) {
+		verifications = append(verifications, v)
+	})
+	require.ErrorIs(t, client.Update(decodeTestHeader(t, testV0ToV1Next)), ErrParentHash)
+	require.Equal(t, trusted, client.Head())
+
+	// ECDSA signed switch to the threshold key, failed commit keeps the head
+	current := decodeTestHeader(t, testV0ToV1Current)
+	errCommit := errors.New("commit")
+	require.ErrorIs(t, client.UpdateWith(current, func() error { return errCommit }), errCommit)
+	require.Equal(t, trusted, client.Head())
+	require.Equal(t, uint64(1), client.History().Leaves())
+	require.Empty(t, events)
+	require.NoError(t, client.Update(current))
+	require.Equal(t, current, client.Head())
+	require.Len(t, events, 1)
+	require.Equal(t, uint64(0x1fdc3f), events[0].Height)
+	require.Equal(t, trusted.MixDigest, events[0].Old)
+	require.Equal(t, current.MixDigest, events[0].New)
+	require.Len(t, events[0].Addresses, 7)
+	require.Nil(t, events[0].GlobalKey)
+	var addrBytes []byte
+	for _, addr := range events[0].Addresses {
+		addrBytes = append(addrBytes, addr[:]...)
+	}
+	require.Equal(t, trusted.MixDigest, common.BytesToHash(crypto.Keccak256(addrBytes)))
+
+	// Signed by the new key
+	require.NoError(t, client.Update(decodeTestHeader(t, testV0ToV1Next)))
+	require.Len(t, events, 1)
+
+	// The failed commit is not reported
+	require.Len(t, verifications, 3)
+	require.ErrorIs(t, verifications[0].Err, ErrParentHash)
+	require.Equal(t, current, verifications[1].Header)
+	require.NoError(t, verifications[1].Err)
+}
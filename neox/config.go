@@ -0,0 +1,151 @@
+package verifier
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ChainConfig is the fork schedule of a Neo X network. Activation times are block
+// timestamps, a nil time means the fork is not scheduled. London is active since
+// genesis on every Neo X network.
+type ChainConfig struct {
+	ShanghaiTime *uint64 // Adds WithdrawalsHash.
+	CancunTime   *uint64 // Adds BlobGasUsed, ExcessBlobGas and ParentBeaconRoot.
+	PragueTime   *uint64 // Adds RequestsHash.
+
+	// Extra is the extra version schedule, it's not enforced if nil.
+	Extra ExtraSchedule
+}
+
+// ExtraFork allows extra Version with one of Schemes from Block on, Schemes are nil for
+// versions without scheme byte. AllowECDSAFallback also allows the ECDSA scheme of
+// Version, e.g. for blocks sealed by ECDSA while threshold signing is unavailable.
+type ExtraFork struct {
+	Block              uint64
+	Version            byte
+	Schemes            []byte
+	AllowECDSAFallback bool
+}
+
+// ExtraSchedule is the extra version schedule in ascending Block order. The forks with
+// the highest Block not above a block number are active at it, several forks at the same
+// Block allow any of them. Versions are ordered by their first fork and never
+// downgraded from the parent version.
+type ExtraSchedule []ExtraFork
+
+// DefaultChainConfig is the fork schedule of Neo X networks with Shanghai since
+// genesis, it doesn't enforce an extra schedule.
+var DefaultChainConfig = &ChainConfig{
+	ShanghaiTime: newUint64(0),
+}
+
+func (c *ChainConfig) IsShanghai(time uint64) bool {
+	return isForked(c.ShanghaiTime, time)
+}
+
+func (c *ChainConfig) IsCancun(time uint64) bool {
+	return isForked(c.CancunTime, time)
+}
+
+func (c *ChainConfig) IsPrague(time uint64) bool {
+	return isForked(c.PragueTime, time)
+}
+
+// CheckExtra checks the version and scheme of current extra are active at its height
+// and don't downgrade the version of parent. A nil config allows any version.
+func (c *ChainConfig) CheckExtra(parent, current *types.Header) error {
+	if c == nil || c.Extra == nil {
+		return nil
+	}
+	extra := current.Extra
+	if len(extra) < 1 {
+		return errors.New("empty extra")
+	}
+	number := current.Number.Uint64()
+	var fork *ExtraFork
+	for _, f := range c.Extra.active(number) {
+		if f.Version == extra[0] {
+			fork = &f
+			break
+		}
+	}
+	if fork == nil {
+		return fmt.Errorf("extra version %d is not active at block %d", extra[0], number)
+	}
+	if fork.Schemes != nil {
+		if len(extra) < 2 {
+			return errors.New("missing extra scheme")
+		}
+		fallback := fork.AllowECDSAFallback && extra[1] == ExtraV1ECDSAScheme
+		if !fallback && !slices.Contains(fork.Schemes, extra[1]) {
+			return fmt.Errorf("extra version %d scheme %d is not active at block %d", extra[0], extra[1], number)
+		}
+	}
+	if len(parent.Extra) > 0 && c.Extra.order(parent.Extra[0]) > c.Extra.order(extra[0]) {
+		return fmt.Errorf("extra version %d downgrades parent version %d", extra[0], parent.Extra[0])
+	}
+	return nil
+}
+
+// Validate checks s is in ascending block order and its versions and schemes are
+// registered.
+func (s ExtraSchedule) Validate() error {
+	for i, f := range s {
+		if i > 0 && f.Block < s[i-1].Block {
+			return fmt.Errorf("extra fork %d is below the previous one", f.Block)
+		}
+		extras := [][]byte{{f.Version}}
+		if f.Schemes != nil {
+			extras = extras[:0]
+			for _, scheme := range f.Schemes {
+				extras = append(extras, []byte{f.Version, scheme})
+			}
+		}
+		if f.AllowECDSAFallback {
+			if f.Schemes == nil {
+				return fmt.Errorf("extra fork %d: ECDSA fallback of version %d without schemes", f.Block, f.Version)
+			}
+			extras = append(extras, []byte{f.Version, ExtraV1ECDSAScheme})
+		}
+		for _, extra := range extras {
+			if _, err := LookupExtraScheme(extra); err != nil {
+				return fmt.Errorf("extra fork %d: %w", f.Block, err)
+			}
+		}
+	}
+	return nil
+}
+
+// active returns the forks active at block number.
+func (s ExtraSchedule) active(number uint64) []ExtraFork {
+	end := 0
+	for end < len(s) && s[end].Block <= number {
+		end++
+	}
+	if end == 0 {
+		return nil
+	}
+	start := end
+	for start > 0 && s[start-1].Block == s[end-1].Block {
+		start--
+	}
+	return s[start:end]
+}
+
+// order returns the index of the first fork of version, -1 if it's not scheduled.
+func (s ExtraSchedule) order(version byte) int {
+	return slices.IndexFunc(s, func(f ExtraFork) bool {
+		return f.Version == version
+	})
+}
+
+func isForked(fork *uint64, time uint64) bool {
+	return fork != nil && *fork <= time
+}
+
+func newUint64(v uint64) *uint64 {
+	return &v
+}
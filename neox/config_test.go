@@ -0,0 +1,172 @@
+package verifier
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeSigHeaderForks(t *testing.T) {
+	newHeader := func() *types.Header {
+		return &types.Header{
+			ParentHash:  common.HexToHash("0x70b8d2a8371cf83d94012459876d326fe236141ea2d8c04ccaa7ba5d4dad19a4"),
+			UncleHash:   types.EmptyUncleHash,
+			Coinbase:    common.HexToAddress("0x1212000000000000000000000000000000000003"),
+			Root:        common.HexToHash("0x73fa78a8689580ed7319392cb2f9d062acece70f938f9b9af6578e15c6ee4aeb"),
+			TxHash:      types.EmptyTxsHash,
+			ReceiptHash: types.EmptyReceiptsHash,
+			Difficulty:  big.NewInt(2),
+			Number:      big.NewInt(0x3aac82),
+			GasLimit:    30000000,
+			Time:        0x68623070,
+			Extra:       common.FromHex("0x0201072bc064323344cba6d63cad4ca88afbea585fc612919e3e351f457ea3704f76"),
+			MixDigest:   common.HexToHash("0x8ff779018b306c26cf13c12aa70002ecb98e553f725049d81bfca73ca5141ec9"),
+			Nonce:       types.EncodeNonce(3),
+			BaseFee:     big.NewInt(20000000000),
+		}
+	}
+	var (
+		zero         = uint64(0)
+		beaconRoot   = common.HexToHash("0x01")
+		requestsHash = types.EmptyRequestsHash
+		withdrawals  = types.EmptyWithdrawalsHash
+		london       = &ChainConfig{}
+		shanghai     = &ChainConfig{ShanghaiTime: &zero}
+		cancun       = &ChainConfig{ShanghaiTime: &zero, CancunTime: &zero}
+		prague       = &ChainConfig{ShanghaiTime: &zero, CancunTime: &zero, PragueTime: &zero}
+		pragueLater  = &ChainConfig{ShanghaiTime: &zero, CancunTime: &zero, PragueTime: newUint64(0x68623071)}
+	)
+	for _, c := range []struct {
+		name   string
+		config *ChainConfig
+		fields func(h *types.Header)
+		hash   string
+	}{
+		{"London", london, func(h *types.Header) {}, "0x2a0f75a2de16023b204fd4388ce2dd9c60a47c03862e8807712456ce62ae06db"},
+		{"Shanghai", shanghai, func(h *types.Header) {
+			h.WithdrawalsHash = &withdrawals
+		}, "0x3bea82a19d7f29fd02f1a5c87a5373811302f14d95c0c6126106b12f897afe68"},
+		{"Cancun", cancun, func(h *types.Header) {
+			h.WithdrawalsHash = &withdrawals
+			h.BlobGasUsed, h.ExcessBlobGas, h.ParentBeaconRoot = &zero, &zero, &beaconRoot
+		}, "0x316ff84be5812869e6b868314a487e1f49942fc6054266a2e1d20286eea5569e"},
+		{"Prague", prague, func(h *types.Header) {
+			h.WithdrawalsHash = &withdrawals
+			h.BlobGasUsed, h.ExcessBlobGas, h.ParentBeaconRoot = &zero, &zero, &beaconRoot
+			h.RequestsHash = &requestsHash
+		}, "0x96dc18da5ef386f0733350e3fd91f7cb78c3489e4a978737558818fce7f1c9d1"},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			header := newHeader()
+			c.fields(header)
+			data, err := encodeSigHeader(c.config, header)
+			require.NoError(t, err)
+			require.Equal(t, common.HexToHash(c.hash), common.BytesToHash(crypto.Keccak256(data)))
+			// Seal hash is the hash of the header with hashable extra only
+			header.Extra = header.Extra[:HashableExtraV1Len]
+			require.Equal(t, header.Hash(), common.BytesToHash(crypto.Keccak256(data)))
+		})
+	}
+
+	// Fields must follow the schedule
+	header := newHeader()
+	_, err := encodeSigHeader(shanghai, header)
+	require.Error(t, err)
+	header.WithdrawalsHash = &withdrawals
+	header.BlobGasUsed, header.ExcessBlobGas, header.ParentBeaconRoot = &zero, &zero, &beaconRoot
+	_, err = encodeSigHeader(shanghai, header)
+	require.Error(t, err)
+	header.RequestsHash = &requestsHash
+	_, err = encodeSigHeader(pragueLater, header)
+	require.Error(t, err)
+	header.Time++
+	_, err = encodeSigHeader(pragueLater, header)
+	require.NoError(t, err)
+}
+
+func TestExtraSchedule(t *testing.T) {
+	config := &ChainConfig{ShanghaiTime: newUint64(0), Extra: ExtraSchedule{
+		{Block: 0, Version: ExtraV0},
+		{Block: 100, Version: ExtraV1, Schemes: []byte{ExtraV1ECDSAScheme}},
+		{Block: 200, Version: ExtraV1, Schemes: []byte{ExtraV1ThresholdScheme}, AllowECDSAFallback: true},
+		{Block: 300, Version: ExtraV1, Schemes: []byte{ExtraV1ThresholdScheme}},
+		{Block: 300, Version: ExtraV2, Schemes: []byte{ExtraV1ThresholdScheme}},
+		{Block: 400, Version: ExtraV2, Schemes: []byte{ExtraV1ThresholdScheme}},
+	}}
+	require.NoError(t, config.Extra.Validate())
+	header := func(number uint64, extra ...byte) *types.Header {
+		return &types.Header{Number: new(big.Int).SetUint64(number), Extra: extra}
+	}
+	for _, c := range []struct {
+		number uint64
+		parent []byte
+		extra  []byte
+		ok     bool
+	}{
+		{99, []byte{ExtraV0}, []byte{ExtraV0}, true},
+		{99, []byte{ExtraV0}, []byte{ExtraV1, ExtraV1ECDSAScheme}, false},
+		{100, []byte{ExtraV0}, []byte{ExtraV0}, false},
+		{100, []byte{ExtraV0}, []byte{ExtraV1, ExtraV1ECDSAScheme}, true},
+		{100, []byte{ExtraV0}, []byte{ExtraV1, ExtraV1ThresholdScheme}, false},
+		{200, []byte{ExtraV1}, []byte{ExtraV1, ExtraV1ThresholdScheme}, true},
+		{200, []byte{ExtraV1}, []byte{ExtraV1}, false},
+		{200, []byte{ExtraV1}, []byte{ExtraV2, ExtraV1ThresholdScheme}, false},
+		// ECDSA fallback
+		{299, []byte{ExtraV1}, []byte{ExtraV1, ExtraV1ECDSAScheme}, true},
+		{300, []byte{ExtraV1}, []byte{ExtraV1, ExtraV1ECDSAScheme}, false},
+		// Forks at the same block
+		{300, []byte{ExtraV1}, []byte{ExtraV1, ExtraV1ThresholdScheme}, true},
+		{300, []byte{ExtraV1}, []byte{ExtraV2, ExtraV1ThresholdScheme}, true},
+		{301, []byte{ExtraV2}, []byte{ExtraV1, ExtraV1ThresholdScheme}, false},
+		{400, []byte{ExtraV1}, []byte{ExtraV1, ExtraV1ThresholdScheme}, false},
+		{400, []byte{ExtraV1}, []byte{ExtraV2, ExtraV1ThresholdScheme}, true},
+		{400, []byte{ExtraV2}, []byte{ExtraV2, 2}, false},
+		{400, []byte{ExtraV2}, nil, false},
+	} {
+		err := config.CheckExtra(header(c.number-1, c.parent...), header(c.number, c.extra...))
+		require.Equal(t, c.ok, err == nil, "%d %x", c.number, c.extra)
+	}
+	require.NoError(t, (&ChainConfig{}).CheckExtra(header(0, ExtraV2), header(1, ExtraV0)))
+	require.Nil(t, DefaultChainConfig.Extra)
+
+	// Upgrades of the fixtures follow their schedule
+	config = &ChainConfig{ShanghaiTime: newUint64(0), Extra: ExtraSchedule{
+		{Block: 0, Version: ExtraV0},
+		{Block: 0x1fdc3f, Version: ExtraV1, Schemes: []byte{ExtraV1ECDSAScheme}},
+		{Block: 0x1fdc40, Version: ExtraV1, Schemes: []byte{ExtraV1ThresholdScheme}},
+		{Block: 0x3aac81, Version: ExtraV2, Schemes: []byte{ExtraV1ThresholdScheme}},
+	}}
+	for _, pair := range [][2]string{
+		{testV0ToV1Parent, testV0ToV1Current},
+		{testV0ToV1Current, testV0ToV1Next},
+		{testV1Parent, testV1Current},
+		{testV2Parent, testV2Current},
+	} {
+		parent, current := decodeTestHeader(t, pair[0]), decodeTestHeader(t, pair[1])
+		require.Equal(t, true, VerifyUpdateHeaderWithConfig(config, parent, current))
+	}
+	// Early upgrade
+	early := &ChainConfig{ShanghaiTime: newUint64(0), Extra: ExtraSchedule{
+		{Block: 0, Version: ExtraV0},
+		{Block: 0x1fdc40, Version: ExtraV1, Schemes: []byte{ExtraV1ECDSAScheme, ExtraV1ThresholdScheme}},
+	}}
+	require.NoError(t, early.Extra.Validate())
+	require.Equal(t, false, VerifyUpdateHeaderWithConfig(early, decodeTestHeader(t, testV0ToV1Parent), decodeTestHeader(t, testV0ToV1Current)))
+	// Downgrade
+	late := &ChainConfig{ShanghaiTime: newUint64(0), Extra: ExtraSchedule{
+		{Block: 0, Version: ExtraV0},
+		{Block: 0x2970d0, Version: ExtraV2, Schemes: []byte{ExtraV1ThresholdScheme}},
+	}}
+	require.Equal(t, false, VerifyUpdateHeaderWithConfig(late, decodeTestHeader(t, testV1Parent), decodeTestHeader(t, testV1Current)))
+
+	// Versions and schemes must be registered
+	require.Error(t, ExtraSchedule{{Block: 0, Version: 0xfd}}.Validate())
+	require.Error(t, ExtraSchedule{{Block: 0, Version: ExtraV1}}.Validate())
+	require.Error(t, ExtraSchedule{{Block: 0, Version: ExtraV1, Schemes: []byte{2}}}.Validate())
+	require.Error(t, ExtraSchedule{{Block: 1, Version: ExtraV0}, {Block: 0, Version: ExtraV0}}.Validate())
+	require.Error(t, ExtraSchedule{{Block: 0, Version: ExtraV0, AllowECDSAFallback: true}}.Validate())
+}
@@ -0,0 +1,156 @@
+package verifier
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// DKGDeal is the Feldman commitments g1^a_k of a dealer polynomial of degree
+// threshold-1 and the shares it revealed, e.g. while answering complaints.
+type DKGDeal struct {
+	Dealer      uint64
+	Commitments []bls12381.G1Affine
+	// Shares are keyed by share index, i.e. the recipient validator index plus one.
+	Shares map[uint64]fr.Element
+}
+
+// DKGTranscript is the public transcript of a key generation.
+type DKGTranscript struct {
+	Threshold int
+	Deals     []DKGDeal
+}
+
+// Verify checks every deal and returns the global key, i.e. the sum of the constant
+// commitments of qualified dealers, with the qualified dealers. Deals with malformed
+// commitments or revealed shares that don't match them are disqualified.
+func (tr *DKGTranscript) Verify() (*bls12381.G1Affine, []uint64, error) {
+	if tr.Threshold <= 0 {
+		return nil, nil, errors.New("invalid threshold")
+	}
+	var (
+		global    bls12381.G1Jac
+		qualified []uint64
+		seen      = make(map[uint64]bool, len(tr.Deals))
+	)
+	for i := range tr.Deals {
+		deal := &tr.Deals[i]
+		if seen[deal.Dealer] {
+			return nil, nil, fmt.Errorf("duplicate dealer %d", deal.Dealer)
+		}
+		seen[deal.Dealer] = true
+		if !deal.verify(tr.Threshold) {
+			continue
+		}
+		qualified = append(qualified, deal.Dealer)
+		global.AddMixed(&deal.Commitments[0])
+	}
+	if len(qualified) < tr.Threshold {
+		return nil, qualified, fmt.Errorf("%d qualified dealers of %d required", len(qualified), tr.Threshold)
+	}
+	pk := new(bls12381.G1Affine).FromJacobian(&global)
+	return pk, qualified, nil
+}
+
+// ShareKey returns the share public key g1^s_i of share index i, that is used to check
+// partial signatures and decryption shares.
+func (tr *DKGTranscript) ShareKey(index uint64) (*bls12381.G1Affine, error) {
+	if index == 0 {
+		return nil, errors.New("invalid share index 0")
+	}
+	if _, _, err := tr.Verify(); err != nil {
+		return nil, err
+	}
+	var sum bls12381.G1Jac
+	for i := range tr.Deals {
+		if !tr.Deals[i].verify(tr.Threshold) {
+			continue
+		}
+		key, err := evalCommitments(tr.Deals[i].Commitments, index)
+		if err != nil {
+			return nil, err
+		}
+		sum.AddMixed(key)
+	}
+	return new(bls12381.G1Affine).FromJacobian(&sum), nil
+}
+
+// GlobalKeyFromHeader returns the global public key from the extra of a verified
+// threshold scheme header.
+func GlobalKeyFromHeader(header *types.Header) (*bls12381.G1Affine, error) {
+	e, err := ParseExtra(header.Extra)
+	if err != nil {
+		return nil, err
+	}
+	if e.GlobalKey == nil {
+		return nil, errors.New("not a threshold scheme header")
+	}
+	return e.GlobalKey, nil
+}
+
+// VerifyGlobalKey checks the global key in the extra of a threshold scheme header is
+// the one generated by the transcript.
+func VerifyGlobalKey(header *types.Header, tr *DKGTranscript) bool {
+	pk, _, err := tr.Verify()
+	if err != nil {
+		return false
+	}
+	extraKey, err := GlobalKeyFromHeader(header)
+	return err == nil && extraKey.Equal(pk)
+}
+
+// VerifyNextGlobalKey checks header commits to the global key generated by the
+// transcript in MixDigest, i.e. the key rotation is signed by the current validators.
+func VerifyNextGlobalKey(header *types.Header, tr *DKGTranscript) bool {
+	pk, _, err := tr.Verify()
+	if err != nil {
+		return false
+	}
+	pub := pk.Bytes()
+	return crypto.Keccak256Hash(pub[:]) == header.MixDigest
+}
+
+func (deal *DKGDeal) verify(threshold int) bool {
+	if len(deal.Commitments) != threshold {
+		return false
+	}
+	for i := range deal.Commitments {
+		if !deal.Commitments[i].IsInSubGroup() {
+			return false
+		}
+	}
+	_, _, g1, _ := bls12381.Generators()
+	for index, share := range deal.Shares {
+		if index == 0 {
+			return false
+		}
+		expected, err := evalCommitments(deal.Commitments, index)
+		if err != nil {
+			return false
+		}
+		var actual bls12381.G1Affine
+		actual.ScalarMultiplication(&g1, share.BigInt(new(big.Int)))
+		if !actual.Equal(expected) {
+			return false
+		}
+	}
+	return true
+}
+
+// evalCommitments returns prod C_k^(x^k), i.e. g1 to the dealer polynomial at x.
+func evalCommitments(commitments []bls12381.G1Affine, x uint64) (*bls12381.G1Affine, error) {
+	powers := make([]fr.Element, len(commitments))
+	var xe fr.Element
+	xe.SetUint64(x)
+	powers[0].SetOne()
+	for k := 1; k < len(powers); k++ {
+		powers[k].Mul(&powers[k-1], &xe)
+	}
+	return new(bls12381.G1Affine).MultiExp(commitments, powers, ecc.MultiExpConfig{})
+}
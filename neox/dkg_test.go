@@ -0,0 +1,106 @@
+package verifier
+
+import (
+	"math/big"
+	"testing"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestDeal returns a deal of a random polynomial of degree threshold-1 with shares
+// for indexes 1..7, and the polynomial.
+func newTestDeal(t testing.TB, dealer uint64, threshold int) (DKGDeal, []fr.Element) {
+	_, _, g1, _ := bls12381.Generators()
+	coeffs := make([]fr.Element, threshold)
+	deal := DKGDeal{Dealer: dealer, Commitments: make([]bls12381.G1Affine, threshold), Shares: make(map[uint64]fr.Element)}
+	for i := range coeffs {
+		_, err := coeffs[i].SetRandom()
+		require.NoError(t, err)
+		deal.Commitments[i].ScalarMultiplication(&g1, coeffs[i].BigInt(new(big.Int)))
+	}
+	for i := uint64(1); i <= 7; i++ {
+		var share, x fr.Element
+		x.SetUint64(i)
+		for j := len(coeffs) - 1; j >= 0; j-- {
+			share.Mul(&share, &x)
+			share.Add(&share, &coeffs[j])
+		}
+		deal.Shares[i] = share
+	}
+	return deal, coeffs
+}
+
+func TestDKGTranscript(t *testing.T) {
+	tr := &DKGTranscript{Threshold: 5}
+	k := new(testThresholdKey)
+	for d := uint64(1); d <= 7; d++ {
+		deal, coeffs := newTestDeal(t, d, 5)
+		tr.Deals = append(tr.Deals, deal)
+		// Dealer 4 is disqualified below
+		if d != 4 {
+			k.secret.Add(&k.secret, &coeffs[0])
+		}
+	}
+	_, _, g1, _ := bls12381.Generators()
+	k.pub.ScalarMultiplication(&g1, k.secret.BigInt(new(big.Int)))
+
+	// Dealer 4 reveals a share that doesn't match its commitments
+	var one fr.Element
+	one.SetOne()
+	bad := tr.Deals[3].Shares[2]
+	bad.Add(&bad, &one)
+	tr.Deals[3].Shares[2] = bad
+
+	pk, qualified, err := tr.Verify()
+	require.NoError(t, err)
+	require.Equal(t, []uint64{1, 2, 3, 5, 6, 7}, qualified)
+	require.Equal(t, true, pk.Equal(&k.pub))
+
+	// Share keys of qualified dealers interpolate to the global key
+	var (
+		xs   []fr.Element
+		keys []bls12381.G1Affine
+	)
+	for i := uint64(1); i <= 5; i++ {
+		key, err := tr.ShareKey(i)
+		require.NoError(t, err)
+		var x fr.Element
+		x.SetUint64(i)
+		xs = append(xs, x)
+		keys = append(keys, *key)
+	}
+	var sum bls12381.G1Jac
+	for i, l := range lagrangeAtZero(xs) {
+		var p bls12381.G1Jac
+		p.FromAffine(&keys[i])
+		p.ScalarMultiplication(&p, l.BigInt(new(big.Int)))
+		sum.AddAssign(&p)
+	}
+	require.Equal(t, true, new(bls12381.G1Affine).FromJacobian(&sum).Equal(&k.pub))
+
+	// Rotation to the generated key
+	parent := newTestGenesis(k.commitment())
+	require.Equal(t, true, VerifyNextGlobalKey(parent, tr))
+	current := nextTestHeader(parent, k.commitment())
+	k.seal(t, current, ExtraV2)
+	require.Equal(t, true, VerifyUpdateHeaderWithConfig(testConfig, parent, current))
+	require.Equal(t, true, VerifyGlobalKey(current, tr))
+	_, err = GlobalKeyFromHeader(parent)
+	require.Error(t, err)
+
+	other := newTestThresholdKey(t)
+	require.Equal(t, false, VerifyNextGlobalKey(newTestGenesis(other.commitment()), tr))
+	other.seal(t, current, ExtraV2)
+	require.Equal(t, false, VerifyGlobalKey(current, tr))
+
+	// Not enough qualified dealers
+	tr.Deals = tr.Deals[:5]
+	_, _, err = tr.Verify()
+	require.Error(t, err)
+	require.Equal(t, false, VerifyGlobalKey(current, tr))
+	tr.Deals = append(tr.Deals, tr.Deals[0])
+	_, _, err = tr.Verify()
+	require.Error(t, err)
+}
@@ -0,0 +1,95 @@
+package verifier
+
+import (
+	"errors"
+	"fmt"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Extra is the content of block Extra for a given version and scheme.
+type Extra struct {
+	Version byte
+	// Scheme is ignored for ExtraV0.
+	Scheme byte
+	// Hashable is the 32-byte field of the hashable part, ignored for ExtraV0.
+	Hashable common.Hash
+	// Addresses and Signatures are used by ExtraV0 and ECDSA scheme. Addresses are in
+	// the committed order and signatures are [R || S || V], ordered by signer address.
+	Addresses  []common.Address
+	Signatures [][]byte
+	// GlobalKey and Signature are used by threshold scheme. Signature is the plain
+	// aggregated signature, it's negated for ExtraV1 as VerifyUpdateHeader expects.
+	GlobalKey *bls12381.G1Affine
+	Signature *bls12381.G2Affine
+}
+
+// HashablePrefix returns the part of Extra covered by the seal hash, so the header can
+// be sealed before the signatures are known.
+func (e *Extra) HashablePrefix() ([]byte, error) {
+	switch e.Version {
+	case ExtraV0:
+		return []byte{ExtraV0}, nil
+	case ExtraV1, ExtraV2:
+		if e.Scheme != ExtraV1ECDSAScheme && e.Scheme != ExtraV1ThresholdScheme {
+			return nil, fmt.Errorf("unexpected extra scheme %d", e.Scheme)
+		}
+		return append([]byte{e.Version, e.Scheme}, e.Hashable[:]...), nil
+	default:
+		return nil, fmt.Errorf("unexpected extra version %d", e.Version)
+	}
+}
+
+// BuildExtra returns the Extra bytes of e.
+func BuildExtra(e *Extra) ([]byte, error) {
+	extra, err := e.HashablePrefix()
+	if err != nil {
+		return nil, err
+	}
+	if e.Version != ExtraV0 && e.Scheme == ExtraV1ThresholdScheme {
+		if e.GlobalKey == nil || e.Signature == nil {
+			return nil, errors.New("missing global key or signature")
+		}
+		sig := *e.Signature
+		// Negate the sig in V1
+		if e.Version == ExtraV1 {
+			sig.Neg(&sig)
+		}
+		pub := e.GlobalKey.Bytes()
+		sigBytes := sig.Bytes()
+		extra = append(extra, pub[:]...)
+		return append(extra, sigBytes[:]...), nil
+	}
+	if len(e.Addresses) != 7 || len(e.Signatures) != 5 {
+		return nil, errors.New("unexpected number of addresses or signatures")
+	}
+	for _, addr := range e.Addresses {
+		extra = append(extra, addr[:]...)
+	}
+	for _, sig := range e.Signatures {
+		if len(sig) != crypto.SignatureLength {
+			return nil, errors.New("malformed signature")
+		}
+		extra = append(extra, sig...)
+	}
+	return extra, nil
+}
+
+// SealData returns the header encoding signed by validators, threshold schemes sign
+// its bls12381.HashToG2 with BLSDomain. Only the hashable part of Extra is used, a nil
+// config is the encoding of VerifyUpdateHeader.
+func SealData(config *ChainConfig, header *types.Header) ([]byte, error) {
+	return encodeSigHeader(config, header)
+}
+
+// SealHash returns the hash signed by validators of ExtraV0 and ECDSA scheme.
+func SealHash(config *ChainConfig, header *types.Header) (common.Hash, error) {
+	data, err := SealData(config, header)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return crypto.Keccak256Hash(data), nil
+}
@@ -0,0 +1,178 @@
+package verifier
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"math/big"
+	"sort"
+	"testing"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+type testValidators struct {
+	privs []*ecdsa.PrivateKey // Sorted by address.
+	addrs []common.Address
+}
+
+func newTestValidators(t testing.TB) *testValidators {
+	v := &testValidators{privs: make([]*ecdsa.PrivateKey, 7)}
+	for i := range v.privs {
+		priv, err := crypto.GenerateKey()
+		require.NoError(t, err)
+		v.privs[i] = priv
+	}
+	sort.Slice(v.privs, func(i, j int) bool {
+		return bytes.Compare(crypto.PubkeyToAddress(v.privs[i].PublicKey).Bytes(), crypto.PubkeyToAddress(v.privs[j].PublicKey).Bytes()) < 0
+	})
+	for _, priv := range v.privs {
+		v.addrs = append(v.addrs, crypto.PubkeyToAddress(priv.PublicKey))
+	}
+	return v
+}
+
+func (v *testValidators) commitment() common.Hash {
+	var data []byte
+	for _, addr := range v.addrs {
+		data = append(data, addr[:]...)
+	}
+	return crypto.Keccak256Hash(data)
+}
+
+// seal signs header with validators at ascending indexes.
+func (v *testValidators) seal(t testing.TB, header *types.Header, version, scheme byte, indexes ...int) {
+	e := &Extra{Version: version, Scheme: scheme, Addresses: v.addrs}
+	prefix, err := e.HashablePrefix()
+	require.NoError(t, err)
+	header.Extra = prefix
+	hash, err := SealHash(nil, header)
+	require.NoError(t, err)
+	for _, i := range indexes {
+		sig, err := crypto.Sign(hash[:], v.privs[i])
+		require.NoError(t, err)
+		e.Signatures = append(e.Signatures, sig)
+	}
+	header.Extra, err = BuildExtra(e)
+	require.NoError(t, err)
+}
+
+type testThresholdKey struct {
+	secret fr.Element
+	pub    bls12381.G1Affine
+}
+
+func newTestThresholdKey(t testing.TB) *testThresholdKey {
+	k := new(testThresholdKey)
+	_, err := k.secret.SetRandom()
+	require.NoError(t, err)
+	_, _, g1, _ := bls12381.Generators()
+	k.pub.ScalarMultiplication(&g1, k.secret.BigInt(new(big.Int)))
+	return k
+}
+
+func (k *testThresholdKey) commitment() common.Hash {
+	pub := k.pub.Bytes()
+	return crypto.Keccak256Hash(pub[:])
+}
+
+func (k *testThresholdKey) seal(t testing.TB, header *types.Header, version byte) {
+	e := &Extra{Version: version, Scheme: ExtraV1ThresholdScheme, GlobalKey: &k.pub}
+	prefix, err := e.HashablePrefix()
+	require.NoError(t, err)
+	header.Extra = prefix
+	data, err := SealData(nil, header)
+	require.NoError(t, err)
+	hash, err := bls12381.HashToG2(data, BLSDomain)
+	require.NoError(t, err)
+	e.Signature = new(bls12381.G2Affine).ScalarMultiplication(&hash, k.secret.BigInt(new(big.Int)))
+	header.Extra, err = BuildExtra(e)
+	require.NoError(t, err)
+}
+
+// nextTestHeader returns an unsealed child of parent committing to mixDigest.
+func nextTestHeader(parent *types.Header, mixDigest common.Hash) *types.Header {
+	number := new(big.Int).Add(parent.Number, big.NewInt(1))
+	return &types.Header{
+		ParentHash:      parent.Hash(),
+		UncleHash:       types.EmptyUncleHash,
+		Coinbase:        DefaultRules.Coinbase,
+		TxHash:          types.EmptyTxsHash,
+		ReceiptHash:     types.EmptyReceiptsHash,
+		Difficulty:      big.NewInt(DiffInTurn),
+		Number:          number,
+		GasLimit:        parent.GasLimit,
+		Time:            parent.Time + 5,
+		MixDigest:       mixDigest,
+		Nonce:           types.EncodeNonce(number.Uint64() % 7),
+		BaseFee:         new(big.Int).Set(DefaultRules.MinBaseFee),
+		WithdrawalsHash: &types.EmptyWithdrawalsHash,
+	}
+}
+
+// testConfig is the fork schedule of synthetic test chains, extra versions aren't
+// scheduled.
+var testConfig = &ChainConfig{ShanghaiTime: newUint64(0)}
+
+func newTestGenesis(mixDigest common.Hash) *types.Header {
+	return &types.Header{
+		Number:    big.NewInt(0),
+		GasLimit:  30000000,
+		Time:      1720000000,
+		MixDigest: mixDigest,
+		Extra:     []byte{ExtraV0},
+	}
+}
+
+func TestBuildExtra(t *testing.T) {
+	v := newTestValidators(t)
+	k := newTestThresholdKey(t)
+	parent := newTestGenesis(v.commitment())
+
+	for _, version := range []byte{ExtraV0, ExtraV1, ExtraV2} {
+		current := nextTestHeader(parent, v.commitment())
+		v.seal(t, current, version, ExtraV1ECDSAScheme, 0, 2, 3, 5, 6)
+		require.Equal(t, true, VerifyUpdateHeaderWithConfig(testConfig, parent, current))
+		// Wrong order
+		v.seal(t, current, version, ExtraV1ECDSAScheme, 6, 2, 3, 5, 0)
+		require.Equal(t, false, VerifyUpdateHeaderWithConfig(testConfig, parent, current))
+	}
+
+	parent.MixDigest = k.commitment()
+	for _, version := range []byte{ExtraV1, ExtraV2} {
+		current := nextTestHeader(parent, k.commitment())
+		k.seal(t, current, version)
+		require.Equal(t, true, VerifyUpdateHeaderWithConfig(testConfig, parent, current))
+		// Signed by another key
+		newTestThresholdKey(t).seal(t, current, version)
+		require.Equal(t, false, VerifyUpdateHeaderWithConfig(testConfig, parent, current))
+	}
+
+	_, err := BuildExtra(&Extra{Version: ExtraV2, Scheme: ExtraV1ThresholdScheme})
+	require.Error(t, err)
+	_, err = BuildExtra(&Extra{Version: ExtraV0, Addresses: v.addrs})
+	require.Error(t, err)
+	_, err = BuildExtra(&Extra{Version: 3})
+	require.Error(t, err)
+	_, err = SealHash(DefaultChainConfig, &types.Header{Extra: []byte{ExtraV1, 0}})
+	require.Error(t, err)
+}
+
+func TestSealHash(t *testing.T) {
+	header := decodeTestHeader(t, testV2Current)
+	data, err := SealData(nil, header)
+	require.NoError(t, err)
+	hash, err := SealHash(nil, header)
+	require.NoError(t, err)
+	require.Equal(t, crypto.Keccak256Hash(data), hash)
+	_, err = rand.Read(header.Extra[HashableExtraV1Len:])
+	require.NoError(t, err)
+	again, err := SealHash(DefaultChainConfig, header)
+	require.NoError(t, err)
+	require.Equal(t, hash, again)
+}
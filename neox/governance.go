@@ -0,0 +1,127 @@
+package verifier
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/ethereum/go-ethereum/trie/trienode"
+)
+
+// GovernanceAddress is the address of Neo X governance system contract, it keeps the
+// list of current consensus addresses.
+var GovernanceAddress = common.HexToAddress("0x1212000000000000000000000000000000000001")
+
+// Governance is the layout of the consensus address list kept by a governance
+// contract as a Solidity dynamic array, i.e. the length is stored at ConsensusSlot and
+// the i-th element at keccak(ConsensusSlot)+i. ConsensusSize is the number of consensus
+// addresses and the list changes at multiples of EpochDuration blocks.
+type Governance struct {
+	Address       common.Address
+	ConsensusSlot common.Hash
+	ConsensusSize int
+	EpochDuration uint64
+}
+
+// ConsensusProof is a storage proof of the consensus address list in the
+// eth_getProof layout.
+type ConsensusProof struct {
+	AccountProof  [][]byte
+	LengthProof   [][]byte
+	AddressProofs [][][]byte
+}
+
+// ConsensusFromProof returns the consensus addresses of the governance contract
+// proven against a verified state root.
+func ConsensusFromProof(root common.Hash, g *Governance, proof *ConsensusProof) ([]common.Address, error) {
+	if g == nil || proof == nil {
+		return nil, errors.New("missing governance or proof")
+	}
+	// Get storage root
+	account, err := accountFromProof(root, g.Address, proof.AccountProof)
+	if err != nil {
+		return nil, err
+	}
+	// Get list length
+	length, err := storageValue(account.Root, g.ConsensusSlot, proof.LengthProof)
+	if err != nil {
+		return nil, fmt.Errorf("length proof: %w", err)
+	}
+	if !length.IsUint64() || length.Uint64() != uint64(g.ConsensusSize) {
+		return nil, fmt.Errorf("list length %s is not the consensus size %d", length, g.ConsensusSize)
+	}
+	if len(proof.AddressProofs) != g.ConsensusSize {
+		return nil, errors.New("unexpected number of address proofs")
+	}
+	// Get list elements
+	base := new(big.Int).SetBytes(crypto.Keccak256(g.ConsensusSlot[:]))
+	addrs := make([]common.Address, len(proof.AddressProofs))
+	for i := range addrs {
+		slot := common.BigToHash(new(big.Int).Add(base, big.NewInt(int64(i))))
+		value, err := storageValue(account.Root, slot, proof.AddressProofs[i])
+		if err != nil {
+			return nil, fmt.Errorf("address %d proof: %w", i, err)
+		}
+		if value.BitLen() > 8*common.AddressLength {
+			return nil, fmt.Errorf("address %d is malformed", i)
+		}
+		addrs[i] = common.BigToAddress(value)
+	}
+	return addrs, nil
+}
+
+// VerifyConsensusProof checks that the governance contract state of a verified header
+// at an epoch boundary commits to the same consensus as its MixDigest, so a new
+// validator set is not taken only on the word of the previous one. It applies to
+// ExtraV0 and ECDSA scheme headers, where MixDigest is keccak of sorted addresses.
+func VerifyConsensusProof(g *Governance, header *types.Header, proof *ConsensusProof) bool {
+	if g == nil || header == nil || header.Number == nil || g.EpochDuration == 0 {
+		return false
+	}
+	if !header.Number.IsUint64() || header.Number.Uint64()%g.EpochDuration != 0 {
+		return false
+	}
+	addrs, err := ConsensusFromProof(header.Root, g, proof)
+	if err != nil {
+		return false
+	}
+	sort.Slice(addrs, func(i, j int) bool {
+		return bytes.Compare(addrs[i][:], addrs[j][:]) < 0
+	})
+	addrBytes := make([]byte, 0, len(addrs)*common.AddressLength)
+	for _, addr := range addrs {
+		addrBytes = append(addrBytes, addr[:]...)
+	}
+	return common.BytesToHash(crypto.Keccak256(addrBytes)) == header.MixDigest
+}
+
+func storageValue(root common.Hash, slot common.Hash, proof [][]byte) (*big.Int, error) {
+	data, err := verifyProof(root, crypto.Keccak256(slot[:]), proof)
+	if err != nil {
+		return nil, err
+	}
+	value := new(big.Int)
+	if data == nil {
+		return value, nil
+	}
+	var content []byte
+	if err := rlp.DecodeBytes(data, &content); err != nil {
+		return nil, err
+	}
+	return value.SetBytes(content), nil
+}
+
+func verifyProof(root common.Hash, key []byte, proof [][]byte) ([]byte, error) {
+	nodes := make(trienode.ProofList, 0, len(proof))
+	for _, node := range proof {
+		nodes = append(nodes, node)
+	}
+	return trie.VerifyProof(root, key, nodes.Set())
+}
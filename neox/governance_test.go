@@ -0,0 +1,105 @@
+package verifier
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/ethereum/go-ethereum/trie/trienode"
+	"github.com/ethereum/go-ethereum/triedb"
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyConsensusProof(t *testing.T) {
+	addrs := []common.Address{
+		common.HexToAddress("0xd10f47396dc6c76ad53546158751582d3e2683ef"),
+		common.HexToAddress("0x0fa7e10abc3b4c9dc768f0fa0a043feb987e2177"),
+		common.HexToAddress("0x2952f909b98424f1e99f641212951c350ea78a0c"),
+	}
+	slot := common.BigToHash(big.NewInt(3))
+	g := &Governance{Address: GovernanceAddress, ConsensusSlot: slot, ConsensusSize: len(addrs), EpochDuration: 100}
+	db := triedb.NewDatabase(rawdb.NewMemoryDatabase(), nil)
+
+	// Governance storage
+	storage := trie.NewEmpty(db)
+	put := func(slot common.Hash, value []byte) {
+		enc, err := rlp.EncodeToBytes(common.TrimLeftZeroes(value))
+		require.NoError(t, err)
+		require.NoError(t, storage.Update(crypto.Keccak256(slot[:]), enc))
+	}
+	put(slot, big.NewInt(int64(len(addrs))).Bytes())
+	base := new(big.Int).SetBytes(crypto.Keccak256(slot[:]))
+	slots := make([]common.Hash, len(addrs))
+	for i, addr := range addrs {
+		slots[i] = common.BigToHash(new(big.Int).Add(base, big.NewInt(int64(i))))
+		put(slots[i], addr[:])
+	}
+
+	// State
+	state := trie.NewEmpty(db)
+	account, err := rlp.EncodeToBytes(&types.StateAccount{Balance: uint256.NewInt(0), Root: storage.Hash(), CodeHash: types.EmptyCodeHash[:]})
+	require.NoError(t, err)
+	require.NoError(t, state.Update(crypto.Keccak256(GovernanceAddress[:]), account))
+
+	prove := func(tr *trie.Trie, key []byte) [][]byte {
+		var proof trienode.ProofList
+		require.NoError(t, tr.Prove(key, &proof))
+		nodes := make([][]byte, len(proof))
+		for i := range proof {
+			nodes[i] = proof[i]
+		}
+		return nodes
+	}
+	proof := &ConsensusProof{
+		AccountProof: prove(state, crypto.Keccak256(GovernanceAddress[:])),
+		LengthProof:  prove(storage, crypto.Keccak256(slot[:])),
+	}
+	for _, s := range slots {
+		proof.AddressProofs = append(proof.AddressProofs, prove(storage, crypto.Keccak256(s[:])))
+	}
+
+	got, err := ConsensusFromProof(state.Hash(), g, proof)
+	require.NoError(t, err)
+	require.Equal(t, addrs, got)
+
+	// Sorted commitment at an epoch boundary
+	header := &types.Header{
+		Number:    big.NewInt(200),
+		Root:      state.Hash(),
+		MixDigest: common.BytesToHash(crypto.Keccak256(addrs[1][:], addrs[2][:], addrs[0][:])),
+	}
+	require.Equal(t, true, VerifyConsensusProof(g, header, proof))
+	header.Number = big.NewInt(201)
+	require.Equal(t, false, VerifyConsensusProof(g, header, proof))
+	header.Number = big.NewInt(200)
+	header.MixDigest = common.BytesToHash(crypto.Keccak256(addrs[0][:], addrs[1][:], addrs[2][:]))
+	require.Equal(t, false, VerifyConsensusProof(g, header, proof))
+	require.Equal(t, false, VerifyConsensusProof(g, nil, proof))
+	require.Equal(t, false, VerifyConsensusProof(g, header, nil))
+	require.Equal(t, false, VerifyConsensusProof(nil, header, proof))
+
+	// Another slot or consensus size
+	other := *g
+	other.ConsensusSlot = common.BigToHash(big.NewInt(4))
+	_, err = ConsensusFromProof(state.Hash(), &other, proof)
+	require.Error(t, err)
+	other = *g
+	other.ConsensusSize = 7
+	_, err = ConsensusFromProof(state.Hash(), &other, proof)
+	require.Error(t, err)
+	_, err = ConsensusFromProof(state.Hash(), g, nil)
+	require.Error(t, err)
+
+	// Incomplete list
+	proof.AddressProofs = proof.AddressProofs[:2]
+	_, err = ConsensusFromProof(state.Hash(), g, proof)
+	require.Error(t, err)
+	_, err = ConsensusFromProof(common.Hash{}, g, proof)
+	require.Error(t, err)
+}
@@ -0,0 +1,161 @@
+package verifier
+
+import (
+	"encoding/binary"
+	"errors"
+	"math/bits"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// MMR is a Merkle Mountain Range accumulator of header hashes. Nodes are
+// keccak(left || right) and the root is keccak(leaf count || bagged peaks), where
+// peaks are bagged from right to left.
+type MMR struct {
+	lock   sync.RWMutex
+	levels [][]common.Hash // levels[h] are the nodes of height h, from left to right.
+}
+
+// MMRProof is a membership proof of the leaf at Index in an MMR of Leaves leaves.
+type MMRProof struct {
+	Index    uint64
+	Leaves   uint64
+	Siblings []common.Hash // From the leaf up to its peak.
+	Peaks    []common.Hash // From the left to the right.
+}
+
+func NewMMR() *MMR {
+	return &MMR{}
+}
+
+func (m *MMR) Append(leaf common.Hash) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	node := leaf
+	for h := 0; ; h++ {
+		if h == len(m.levels) {
+			m.levels = append(m.levels, nil)
+		}
+		m.levels[h] = append(m.levels[h], node)
+		n := len(m.levels[h])
+		if n%2 != 0 {
+			return
+		}
+		node = hashMMRNode(m.levels[h][n-2], m.levels[h][n-1])
+	}
+}
+
+func (m *MMR) Leaves() uint64 {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.leaves()
+}
+
+func (m *MMR) Root() common.Hash {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return bagMMRPeaks(m.leaves(), m.peaks())
+}
+
+// Prove returns the membership proof of the leaf at index.
+func (m *MMR) Prove(index uint64) (*MMRProof, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	n := m.leaves()
+	if index >= n {
+		return nil, errors.New("leaf index out of range")
+	}
+	proof := &MMRProof{
+		Index:  index,
+		Leaves: n,
+		Peaks:  m.peaks(),
+	}
+	h, _ := mmrPeakOf(n, index)
+	pos := index
+	for l := range h {
+		proof.Siblings = append(proof.Siblings, m.levels[l][pos^1])
+		pos >>= 1
+	}
+	return proof, nil
+}
+
+// VerifyMMRProof checks that leaf is in the MMR with the given root.
+func VerifyMMRProof(root common.Hash, leaf common.Hash, proof *MMRProof) bool {
+	if proof == nil || proof.Index >= proof.Leaves || len(proof.Peaks) != bits.OnesCount64(proof.Leaves) {
+		return false
+	}
+	h, k := mmrPeakOf(proof.Leaves, proof.Index)
+	if len(proof.Siblings) != h {
+		return false
+	}
+	node := leaf
+	pos := proof.Index
+	for _, sibling := range proof.Siblings {
+		if pos%2 == 0 {
+			node = hashMMRNode(node, sibling)
+		} else {
+			node = hashMMRNode(sibling, node)
+		}
+		pos >>= 1
+	}
+	if node != proof.Peaks[k] {
+		return false
+	}
+	return bagMMRPeaks(proof.Leaves, proof.Peaks) == root
+}
+
+func (m *MMR) leaves() uint64 {
+	if len(m.levels) == 0 {
+		return 0
+	}
+	return uint64(len(m.levels[0]))
+}
+
+func (m *MMR) peaks() []common.Hash {
+	n := m.leaves()
+	var peaks []common.Hash
+	var offset uint64
+	for h := bits.Len64(n) - 1; h >= 0; h-- {
+		if n&(1<<h) != 0 {
+			peaks = append(peaks, m.levels[h][offset>>h])
+			offset += 1 << h
+		}
+	}
+	return peaks
+}
+
+// mmrPeakOf returns the height of the peak covering the leaf at index and its
+// position among the peaks.
+func mmrPeakOf(n, index uint64) (int, int) {
+	var offset uint64
+	var k int
+	for h := bits.Len64(n) - 1; h >= 0; h-- {
+		if n&(1<<h) == 0 {
+			continue
+		}
+		if index < offset+1<<h {
+			return h, k
+		}
+		offset += 1 << h
+		k++
+	}
+	return 0, k
+}
+
+func bagMMRPeaks(n uint64, peaks []common.Hash) common.Hash {
+	var bag common.Hash
+	for i := len(peaks) - 1; i >= 0; i-- {
+		if i == len(peaks)-1 {
+			bag = peaks[i]
+			continue
+		}
+		bag = hashMMRNode(peaks[i], bag)
+	}
+	return crypto.Keccak256Hash(binary.BigEndian.AppendUint64(nil, n), bag[:])
+}
+
+func hashMMRNode(left, right common.Hash) common.Hash {
+	return crypto.Keccak256Hash(left[:], right[:])
+}
@@ -0,0 +1,59 @@
+package verifier
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMMR(t *testing.T) {
+	m := NewMMR()
+	require.Equal(t, uint64(0), m.Leaves())
+	_, err := m.Prove(0)
+	require.Error(t, err)
+
+	leaves := make([]common.Hash, 11)
+	for i := range leaves {
+		leaves[i] = crypto.Keccak256Hash([]byte{byte(i)})
+		m.Append(leaves[i])
+		root := m.Root()
+		for j := 0; j <= i; j++ {
+			proof, err := m.Prove(uint64(j))
+			require.NoError(t, err)
+			require.Equal(t, true, VerifyMMRProof(root, leaves[j], proof))
+			if i > 0 {
+				require.Equal(t, false, VerifyMMRProof(root, leaves[(j+1)%(i+1)], proof))
+			}
+		}
+	}
+	// 11 leaves are 3 peaks of heights 3, 1 and 0
+	proof, err := m.Prove(9)
+	require.NoError(t, err)
+	require.Len(t, proof.Peaks, 3)
+	require.Len(t, proof.Siblings, 1)
+	require.Equal(t, hashMMRNode(leaves[8], leaves[9]), proof.Peaks[1])
+
+	// Tampered proofs
+	root := m.Root()
+	proof.Leaves = 10
+	require.Equal(t, false, VerifyMMRProof(root, leaves[9], proof))
+	proof.Leaves = 11
+	proof.Siblings[0] = leaves[7]
+	require.Equal(t, false, VerifyMMRProof(root, leaves[9], proof))
+	require.Equal(t, false, VerifyMMRProof(root, leaves[9], nil))
+}
+
+func TestLightClientHistory(t *testing.T) {
+	trusted := decodeTestHeader(t, testV0ToV1Parent)
+	client := NewLightClient(DefaultChainConfig, trusted)
+	current := decodeTestHeader(t, testV0ToV1Current)
+	require.NoError(t, client.Update(current))
+
+	root := client.History().Root()
+	proof, err := client.History().Prove(1)
+	require.NoError(t, err)
+	require.Equal(t, true, VerifyMMRProof(root, current.Hash(), proof))
+	require.Equal(t, false, VerifyMMRProof(root, trusted.Hash(), proof))
+}
@@ -0,0 +1,43 @@
+package verifier
+
+import (
+	"errors"
+	"slices"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ErrHiddenSigners is returned for threshold scheme headers, the aggregated signature
+// doesn't reveal the signers.
+var ErrHiddenSigners = errors.New("threshold signature doesn't reveal signers")
+
+// Signers returns the validators committed in the extra of a verified ExtraV0 or ECDSA
+// scheme header and the indexes of the validators whose signatures are in it.
+func Signers(config *ChainConfig, header *types.Header) ([]common.Address, []int, error) {
+	e, err := ParseExtra(header.Extra)
+	if err != nil {
+		return nil, nil, err
+	}
+	if e.GlobalKey != nil {
+		return nil, nil, ErrHiddenSigners
+	}
+	hash, err := SealHash(config, header)
+	if err != nil {
+		return nil, nil, err
+	}
+	signers := make([]int, 0, len(e.Signatures))
+	for _, sig := range e.Signatures {
+		addr, err := recoverSigner(hash[:], sig)
+		if err != nil {
+			return nil, nil, ErrSignature
+		}
+		i := slices.Index(e.Addresses, addr)
+		if i < 0 || slices.Contains(signers, i) {
+			return nil, nil, ErrSignature
+		}
+		signers = append(signers, i)
+	}
+	slices.Sort(signers)
+	return e.Addresses, signers, nil
+}
@@ -0,0 +1,36 @@
+package verifier
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigners(t *testing.T) {
+	current := new(types.Header)
+	require.NoError(t, current.UnmarshalJSON([]byte(testV0Current)))
+	validators, signers, err := Signers(DefaultChainConfig, current)
+	require.NoError(t, err)
+	require.Equal(t, 7, len(validators))
+	require.Equal(t, 5, len(signers))
+
+	v := newTestValidators(t)
+	header := nextTestHeader(newTestGenesis(v.commitment()), v.commitment())
+	v.seal(t, header, ExtraV2, ExtraV1ECDSAScheme, 1, 2, 4, 5, 6)
+	validators, signers, err = Signers(DefaultChainConfig, header)
+	require.NoError(t, err)
+	require.Equal(t, v.addrs, validators)
+	require.Equal(t, []int{1, 2, 4, 5, 6}, signers)
+
+	// Signer missing from the committed addresses
+	header.Extra[2+32+common.AddressLength] ^= 1
+	_, _, err = Signers(DefaultChainConfig, header)
+	require.ErrorIs(t, err, ErrSignature)
+
+	k := newTestThresholdKey(t)
+	k.seal(t, header, ExtraV2)
+	_, _, err = Signers(DefaultChainConfig, header)
+	require.ErrorIs(t, err, ErrHiddenSigners)
+}
@@ -0,0 +1,63 @@
+package verifier
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// VerifyTxProof returns the binary encoding of the transaction at index in a verified
+// header, proven by the nodes of its transaction trie.
+func VerifyTxProof(header *types.Header, index uint64, proof [][]byte) ([]byte, error) {
+	return verifyIndexedProof(header.TxHash, index, proof)
+}
+
+// VerifyReceiptProof returns the consensus encoding of the receipt at index in a
+// verified header, proven by the nodes of its receipt trie.
+func VerifyReceiptProof(header *types.Header, index uint64, proof [][]byte) ([]byte, error) {
+	return verifyIndexedProof(header.ReceiptHash, index, proof)
+}
+
+// VerifyStorageProof returns the value of a storage slot of contract in the state of
+// a verified header, proven by eth_getProof account and storage proofs.
+func VerifyStorageProof(header *types.Header, contract common.Address, slot common.Hash, accountProof, storageProof [][]byte) (common.Hash, error) {
+	account, err := accountFromProof(header.Root, contract, accountProof)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	value, err := storageValue(account.Root, slot, storageProof)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("storage proof: %w", err)
+	}
+	return common.BigToHash(value), nil
+}
+
+func verifyIndexedProof(root common.Hash, index uint64, proof [][]byte) ([]byte, error) {
+	value, err := verifyProof(root, rlp.AppendUint64(nil, index), proof)
+	if err != nil {
+		return nil, err
+	}
+	if value == nil {
+		return nil, errors.New("index not found")
+	}
+	return value, nil
+}
+
+func accountFromProof(root common.Hash, contract common.Address, proof [][]byte) (*types.StateAccount, error) {
+	data, err := verifyProof(root, crypto.Keccak256(contract[:]), proof)
+	if err != nil {
+		return nil, fmt.Errorf("account proof: %w", err)
+	}
+	if data == nil {
+		return nil, errors.New("contract not found")
+	}
+	account := new(types.StateAccount)
+	if err := rlp.DecodeBytes(data, account); err != nil {
+		return nil, err
+	}
+	return account, nil
+}
@@ -0,0 +1,46 @@
+package verifier
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/ethereum/go-ethereum/trie/trienode"
+	"github.com/ethereum/go-ethereum/triedb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyTxProof(t *testing.T) {
+	to := common.HexToAddress("0x1212000000000000000000000000000000000004")
+	txs := make(types.Transactions, 3)
+	tr := trie.NewEmpty(triedb.NewDatabase(rawdb.NewMemoryDatabase(), nil))
+	for i := range txs {
+		txs[i] = types.NewTx(&types.LegacyTx{Nonce: uint64(i), GasPrice: big.NewInt(20000000000), Gas: 21000, To: &to})
+		data, err := txs[i].MarshalBinary()
+		require.NoError(t, err)
+		require.NoError(t, tr.Update(rlp.AppendUint64(nil, uint64(i)), data))
+	}
+	header := &types.Header{TxHash: types.DeriveSha(txs, trie.NewStackTrie(nil))}
+	require.Equal(t, header.TxHash, tr.Hash())
+
+	var proof trienode.ProofList
+	require.NoError(t, tr.Prove(rlp.AppendUint64(nil, 1), &proof))
+	nodes := make([][]byte, len(proof))
+	for i := range proof {
+		nodes[i] = proof[i]
+	}
+	data, err := VerifyTxProof(header, 1, nodes)
+	require.NoError(t, err)
+	tx := new(types.Transaction)
+	require.NoError(t, tx.UnmarshalBinary(data))
+	require.Equal(t, txs[1].Hash(), tx.Hash())
+
+	_, err = VerifyTxProof(header, 2, nodes)
+	require.Error(t, err)
+	_, err = VerifyReceiptProof(header, 1, nodes)
+	require.Error(t, err)
+}
@@ -0,0 +1,123 @@
+// Package rpctest provides an in-process Neo X JSON-RPC server for tests.
+package rpctest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Server is an in-process Neo X JSON-RPC server replaying recorded headers, it
+// answers eth_blockNumber, eth_getBlockByNumber and eth_getBlockByHash, single or
+// batched.
+type Server struct {
+	*httptest.Server
+
+	lock     sync.Mutex
+	byNumber map[uint64]json.RawMessage
+	byHash   map[common.Hash]json.RawMessage
+	height   uint64
+	failures int
+	requests int
+}
+
+// NewServer starts Server with recorded headers, it must be closed.
+func NewServer(headers []json.RawMessage) (*Server, error) {
+	s := &Server{
+		byNumber: make(map[uint64]json.RawMessage, len(headers)),
+		byHash:   make(map[common.Hash]json.RawMessage, len(headers)),
+	}
+	for _, raw := range headers {
+		if err := s.add(raw); err != nil {
+			return nil, err
+		}
+	}
+	srv := rpc.NewServer()
+	if err := srv.RegisterName("eth", &mockEthAPI{s}); err != nil {
+		return nil, err
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.lock.Lock()
+		s.requests++
+		fail := s.failures > 0
+		if fail {
+			s.failures--
+		}
+		s.lock.Unlock()
+		if fail {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		srv.ServeHTTP(w, r)
+	}))
+	return s, nil
+}
+
+// AddHeader makes a recorded header available, e.g. to emulate a growing chain.
+func (s *Server) AddHeader(raw json.RawMessage) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.add(raw)
+}
+
+// FailNext makes the next n HTTP requests fail with 503.
+func (s *Server) FailNext(n int) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.failures = n
+}
+
+// Requests returns the number of HTTP requests served.
+func (s *Server) Requests() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.requests
+}
+
+func (s *Server) add(raw json.RawMessage) error {
+	var header struct {
+		Hash   common.Hash    `json:"hash"`
+		Number hexutil.Uint64 `json:"number"`
+	}
+	if err := json.Unmarshal(raw, &header); err != nil {
+		return err
+	}
+	s.byNumber[uint64(header.Number)] = raw
+	s.byHash[header.Hash] = raw
+	s.height = max(s.height, uint64(header.Number))
+	return nil
+}
+
+type mockEthAPI struct {
+	s *Server
+}
+
+func (api *mockEthAPI) BlockNumber() hexutil.Uint64 {
+	api.s.lock.Lock()
+	defer api.s.lock.Unlock()
+	return hexutil.Uint64(api.s.height)
+}
+
+// GetBlockByNumber returns null for unknown blocks like geth does.
+func (api *mockEthAPI) GetBlockByNumber(number rpc.BlockNumber, fullTx bool) json.RawMessage {
+	api.s.lock.Lock()
+	defer api.s.lock.Unlock()
+	if number == rpc.LatestBlockNumber {
+		return api.s.byNumber[api.s.height]
+	}
+	if number < 0 {
+		return nil
+	}
+	return api.s.byNumber[uint64(number)]
+}
+
+func (api *mockEthAPI) GetBlockByHash(hash common.Hash, fullTx bool) json.RawMessage {
+	api.s.lock.Lock()
+	defer api.s.lock.Unlock()
+	return api.s.byHash[hash]
+}
@@ -0,0 +1,109 @@
+package verifier
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+const (
+	// DiffInTurn is the block difficulty when the block is proposed by the primary of
+	// view 0.
+	DiffInTurn = 2
+	// DiffNoTurn is the block difficulty when the block is proposed after a view change.
+	DiffNoTurn = 1
+)
+
+// Rules are the consensus rules of a Neo X network that are not covered by the block
+// signature, i.e. a validly signed header can still violate them.
+type Rules struct {
+	// MinBaseFee is the lower bound of base fee. It's a setting of the Neo X policy
+	// contract, callers following the contract supply its current value.
+	MinBaseFee *big.Int
+	// Coinbase is the system address every block reward is paid to.
+	Coinbase common.Address
+}
+
+// DefaultRules are the rules of Neo X MainNet and TestNet. MinBaseFee is the static
+// default of 20 GWei, not read from the policy contract.
+var DefaultRules = Rules{
+	MinBaseFee: big.NewInt(20 * params.GWei),
+	Coinbase:   common.HexToAddress("0x1212000000000000000000000000000000000003"),
+}
+
+// VerifyHeaderRules is an optional layer on top of VerifyUpdateHeader, it checks the
+// header fields that validators are free to sign whatever they are. It fails without
+// rules.
+func VerifyHeaderRules(parent, current *types.Header, rules *Rules) bool {
+	if rules == nil {
+		return false
+	}
+	// Check gas
+	if current.GasUsed > current.GasLimit {
+		return false
+	}
+	if current.GasLimit < params.MinGasLimit {
+		return false
+	}
+	limit := parent.GasLimit / params.GasLimitBoundDivisor
+	if current.GasLimit > parent.GasLimit && current.GasLimit-parent.GasLimit >= limit {
+		return false
+	}
+	if current.GasLimit < parent.GasLimit && parent.GasLimit-current.GasLimit >= limit {
+		return false
+	}
+	// Check base fee, the target gas of parents below the minimum limit can be 0
+	if parent.GasLimit < params.MinGasLimit {
+		return false
+	}
+	if current.BaseFee == nil || current.BaseFee.Cmp(calcBaseFee(parent, rules.MinBaseFee)) != 0 {
+		return false
+	}
+	// Check consensus fields
+	if current.Difficulty == nil || !current.Difficulty.IsUint64() {
+		return false
+	}
+	if d := current.Difficulty.Uint64(); d != DiffInTurn && d != DiffNoTurn {
+		return false
+	}
+	if current.UncleHash != types.EmptyUncleHash {
+		return false
+	}
+	return current.Coinbase == rules.Coinbase
+}
+
+// calcBaseFee follows EIP-1559 and bounds the result by the policy minimum.
+func calcBaseFee(parent *types.Header, min *big.Int) *big.Int {
+	fee := new(big.Int)
+	if parent.BaseFee == nil {
+		fee.SetUint64(params.InitialBaseFee)
+	} else {
+		target := new(big.Int).SetUint64(parent.GasLimit / params.DefaultElasticityMultiplier)
+		used := new(big.Int).SetUint64(parent.GasUsed)
+		switch used.Cmp(target) {
+		case 0:
+			fee.Set(parent.BaseFee)
+		case 1:
+			delta := new(big.Int).Sub(used, target)
+			delta.Mul(delta, parent.BaseFee)
+			delta.Div(delta, target)
+			delta.Div(delta, big.NewInt(params.DefaultBaseFeeChangeDenominator))
+			if delta.Sign() == 0 {
+				delta.SetUint64(1)
+			}
+			fee.Add(parent.BaseFee, delta)
+		case -1:
+			delta := new(big.Int).Sub(target, used)
+			delta.Mul(delta, parent.BaseFee)
+			delta.Div(delta, target)
+			delta.Div(delta, big.NewInt(params.DefaultBaseFeeChangeDenominator))
+			fee.Sub(parent.BaseFee, delta)
+		}
+	}
+	if min != nil && fee.Cmp(min) < 0 {
+		fee.Set(min)
+	}
+	return fee
+}
@@ -0,0 +1,69 @@
+package verifier
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyHeaderRules(t *testing.T) {
+	newHeader := func() *types.Header {
+		return &types.Header{
+			UncleHash:  types.EmptyUncleHash,
+			Coinbase:   DefaultRules.Coinbase,
+			Difficulty: big.NewInt(DiffInTurn),
+			GasLimit:   30000000,
+			BaseFee:    big.NewInt(20000000000),
+		}
+	}
+	parent, current := newHeader(), newHeader()
+	require.Equal(t, true, VerifyHeaderRules(parent, current, &DefaultRules))
+
+	// Base fee
+	parent.GasUsed = parent.GasLimit
+	require.Equal(t, false, VerifyHeaderRules(parent, current, &DefaultRules))
+	current.BaseFee = big.NewInt(22500000000)
+	require.Equal(t, true, VerifyHeaderRules(parent, current, &DefaultRules))
+	parent.GasUsed = 0
+	require.Equal(t, false, VerifyHeaderRules(parent, current, &DefaultRules))
+	current.BaseFee = big.NewInt(17500000000)
+	require.Equal(t, true, VerifyHeaderRules(parent, current, &Rules{Coinbase: DefaultRules.Coinbase}))
+	require.Equal(t, false, VerifyHeaderRules(parent, current, &DefaultRules))
+
+	// Gas
+	current = newHeader()
+	current.GasUsed = current.GasLimit + 1
+	require.Equal(t, false, VerifyHeaderRules(parent, current, &DefaultRules))
+	current = newHeader()
+	current.GasLimit = parent.GasLimit + parent.GasLimit/1024
+	require.Equal(t, false, VerifyHeaderRules(parent, current, &DefaultRules))
+	current.GasLimit = parent.GasLimit - parent.GasLimit/1024 + 1
+	require.Equal(t, true, VerifyHeaderRules(parent, current, &DefaultRules))
+
+	// Consensus fields
+	current = newHeader()
+	current.Difficulty = big.NewInt(DiffNoTurn)
+	require.Equal(t, true, VerifyHeaderRules(parent, current, &DefaultRules))
+	current.Difficulty = big.NewInt(3)
+	require.Equal(t, false, VerifyHeaderRules(parent, current, &DefaultRules))
+	current = newHeader()
+	current.UncleHash = common.Hash{}
+	require.Equal(t, false, VerifyHeaderRules(parent, current, &DefaultRules))
+	current = newHeader()
+	current.Coinbase = common.Address{}
+	require.Equal(t, false, VerifyHeaderRules(parent, current, &DefaultRules))
+	require.Equal(t, false, VerifyHeaderRules(parent, newHeader(), nil))
+
+	// Parent below the minimum gas limit
+	parent, current = newHeader(), newHeader()
+	parent.GasLimit, current.GasLimit = params.MinGasLimit, params.MinGasLimit
+	require.Equal(t, true, VerifyHeaderRules(parent, current, &DefaultRules))
+	parent.GasLimit = params.MinGasLimit - 1
+	require.Equal(t, false, VerifyHeaderRules(parent, current, &DefaultRules))
+	parent.GasLimit, parent.GasUsed = 1, 1
+	require.Equal(t, false, VerifyHeaderRules(parent, current, &DefaultRules))
+}
@@ -0,0 +1,211 @@
+package verifier
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// ExtraScheme decodes and verifies block Extra of a registered version and scheme.
+type ExtraScheme interface {
+	// HashableLen is the length of the Extra prefix covered by the seal hash.
+	HashableLen() int
+	// Parse decodes the whole Extra, it fails if extra is malformed.
+	Parse(extra []byte) (*Extra, error)
+	// Commitment is the MixDigest the parent commits to for the signers of e.
+	Commitment(e *Extra) common.Hash
+	// Verify checks the signatures of e over the seal data.
+	Verify(e *Extra, sealData []byte) bool
+}
+
+type extraVersion struct {
+	// schemeless versions have no scheme byte, e.g. ExtraV0.
+	schemeless ExtraScheme
+	schemes    map[byte]ExtraScheme
+}
+
+var (
+	extraSchemesLock sync.RWMutex
+	extraSchemes     = make(map[byte]*extraVersion)
+)
+
+func init() {
+	RegisterExtraVersion(ExtraV0, &ecdsaScheme{hashableLen: HashableExtraV0Len})
+	for _, version := range []byte{ExtraV1, ExtraV2} {
+		RegisterExtraScheme(version, ExtraV1ECDSAScheme, &ecdsaScheme{hashableLen: HashableExtraV1Len})
+		RegisterExtraScheme(version, ExtraV1ThresholdScheme, &thresholdScheme{negated: version == ExtraV1})
+	}
+}
+
+// RegisterExtraScheme registers s for Extra starting with version and scheme bytes. It
+// panics if the pair is already registered or version has no scheme byte.
+func RegisterExtraScheme(version, scheme byte, s ExtraScheme) {
+	extraSchemesLock.Lock()
+	defer extraSchemesLock.Unlock()
+	v, ok := extraSchemes[version]
+	if !ok {
+		v = &extraVersion{schemes: make(map[byte]ExtraScheme)}
+		extraSchemes[version] = v
+	}
+	if v.schemeless != nil {
+		panic(fmt.Sprintf("extra version %d has no scheme", version))
+	}
+	if _, ok := v.schemes[scheme]; ok {
+		panic(fmt.Sprintf("extra version %d scheme %d is already registered", version, scheme))
+	}
+	v.schemes[scheme] = s
+}
+
+// RegisterExtraVersion registers s for Extra starting with version byte without scheme
+// byte. It panics if version is already registered.
+func RegisterExtraVersion(version byte, s ExtraScheme) {
+	extraSchemesLock.Lock()
+	defer extraSchemesLock.Unlock()
+	if _, ok := extraSchemes[version]; ok {
+		panic(fmt.Sprintf("extra version %d is already registered", version))
+	}
+	extraSchemes[version] = &extraVersion{schemeless: s}
+}
+
+// LookupExtraScheme returns the registered scheme of extra.
+func LookupExtraScheme(extra []byte) (ExtraScheme, error) {
+	if len(extra) < 1 {
+		return nil, errors.New("empty extra")
+	}
+	extraSchemesLock.RLock()
+	defer extraSchemesLock.RUnlock()
+	v, ok := extraSchemes[extra[0]]
+	if !ok {
+		return nil, fmt.Errorf("unexpected extra version %d", extra[0])
+	}
+	if v.schemeless != nil {
+		return v.schemeless, nil
+	}
+	if len(extra) < 2 {
+		return nil, errors.New("missing extra scheme")
+	}
+	s, ok := v.schemes[extra[1]]
+	if !ok {
+		return nil, fmt.Errorf("unexpected extra scheme %d", extra[1])
+	}
+	return s, nil
+}
+
+// ParseExtra decodes extra with its registered scheme.
+func ParseExtra(extra []byte) (*Extra, error) {
+	s, err := LookupExtraScheme(extra)
+	if err != nil {
+		return nil, err
+	}
+	return s.Parse(extra)
+}
+
+// ecdsaScheme is ExtraV0 and the ECDSA scheme, 7 addresses followed by 5 signatures.
+type ecdsaScheme struct {
+	hashableLen int
+}
+
+func (s *ecdsaScheme) HashableLen() int {
+	return s.hashableLen
+}
+
+func (s *ecdsaScheme) Parse(extra []byte) (*Extra, error) {
+	if len(extra) != s.hashableLen+7*common.AddressLength+5*crypto.SignatureLength {
+		return nil, errors.New("unexpected extra length")
+	}
+	e := parseHashable(extra, s.hashableLen)
+	addrBytes := extra[s.hashableLen : s.hashableLen+7*common.AddressLength]
+	sigBytes := extra[s.hashableLen+7*common.AddressLength:]
+	e.Addresses = make([]common.Address, 7)
+	for i := range e.Addresses {
+		copy(e.Addresses[i][:], addrBytes[i*common.AddressLength:(i+1)*common.AddressLength])
+	}
+	e.Signatures = make([][]byte, 5)
+	for i := range e.Signatures {
+		e.Signatures[i] = sigBytes[i*crypto.SignatureLength : (i+1)*crypto.SignatureLength]
+	}
+	return e, nil
+}
+
+func (s *ecdsaScheme) Commitment(e *Extra) common.Hash {
+	var data []byte
+	for _, addr := range e.Addresses {
+		data = append(data, addr[:]...)
+	}
+	return crypto.Keccak256Hash(data)
+}
+
+func (s *ecdsaScheme) Verify(e *Extra, sealData []byte) bool {
+	if len(e.Signatures) != 5 {
+		return false
+	}
+	for _, sig := range e.Signatures {
+		if len(sig) != crypto.SignatureLength {
+			return false
+		}
+	}
+	return verifyMultiSigs(crypto.Keccak256(sealData), e.Signatures, slices.Clone(e.Addresses))
+}
+
+// thresholdScheme is the threshold scheme, global public key followed by aggregated
+// signature, that is negated in ExtraV1.
+type thresholdScheme struct {
+	negated bool
+}
+
+func (s *thresholdScheme) HashableLen() int {
+	return HashableExtraV1Len
+}
+
+func (s *thresholdScheme) Parse(extra []byte) (*Extra, error) {
+	if len(extra) != HashableExtraV1Len+BLSPublicKeyLen+BLSSignatureLen {
+		return nil, errors.New("unexpected extra length")
+	}
+	e := parseHashable(extra, HashableExtraV1Len)
+	e.GlobalKey = new(bls12381.G1Affine)
+	if _, err := e.GlobalKey.SetBytes(extra[HashableExtraV1Len : HashableExtraV1Len+BLSPublicKeyLen]); err != nil {
+		return nil, err
+	}
+	e.Signature = new(bls12381.G2Affine)
+	if _, err := e.Signature.SetBytes(extra[HashableExtraV1Len+BLSPublicKeyLen:]); err != nil {
+		return nil, err
+	}
+	if s.negated {
+		e.Signature.Neg(e.Signature)
+	}
+	return e, nil
+}
+
+func (s *thresholdScheme) Commitment(e *Extra) common.Hash {
+	if e.GlobalKey == nil {
+		return common.Hash{}
+	}
+	pub := e.GlobalKey.Bytes()
+	return crypto.Keccak256Hash(pub[:])
+}
+
+func (s *thresholdScheme) Verify(e *Extra, sealData []byte) bool {
+	if e.GlobalKey == nil || e.Signature == nil {
+		return false
+	}
+	hash, err := bls12381.HashToG2(sealData, BLSDomain)
+	if err != nil {
+		return false
+	}
+	return verifyBLSSig(hash, e.Signature, e.GlobalKey)
+}
+
+// parseHashable returns Extra with the version, scheme and hashable field.
+func parseHashable(extra []byte, hashableLen int) *Extra {
+	e := &Extra{Version: extra[0]}
+	if hashableLen >= HashableExtraV1Len {
+		e.Scheme = extra[1]
+		copy(e.Hashable[:], extra[2:HashableExtraV1Len])
+	}
+	return e
+}
@@ -0,0 +1,93 @@
+package verifier
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+const testExtraVersion byte = 0xfe
+
+// testScheme is sealed by the keccak of seal data, committed by a fixed hash.
+type testScheme struct{}
+
+var testSchemeCommitment = common.Hash{0xfe}
+
+// registerTestScheme registers testScheme as testExtraVersion for the duration of t.
+func registerTestScheme(t *testing.T) {
+	RegisterExtraVersion(testExtraVersion, testScheme{})
+	t.Cleanup(func() {
+		extraSchemesLock.Lock()
+		defer extraSchemesLock.Unlock()
+		delete(extraSchemes, testExtraVersion)
+	})
+}
+
+func (testScheme) HashableLen() int { return 1 }
+
+func (testScheme) Parse(extra []byte) (*Extra, error) {
+	if len(extra) != 1+common.HashLength {
+		return nil, errors.New("unexpected extra length")
+	}
+	return &Extra{Version: extra[0], Signatures: [][]byte{extra[1:]}}, nil
+}
+
+func (testScheme) Commitment(*Extra) common.Hash { return testSchemeCommitment }
+
+func (testScheme) Verify(e *Extra, sealData []byte) bool {
+	return common.BytesToHash(e.Signatures[0]) == crypto.Keccak256Hash(sealData)
+}
+
+func TestExtraScheme(t *testing.T) {
+	_, err := LookupExtraScheme([]byte{testExtraVersion})
+	require.Error(t, err)
+	registerTestScheme(t)
+	v := newTestValidators(t)
+	k := newTestThresholdKey(t)
+
+	// Registered schemes round trip with BuildExtra
+	header := nextTestHeader(newTestGenesis(v.commitment()), v.commitment())
+	for _, version := range []byte{ExtraV0, ExtraV1, ExtraV2} {
+		v.seal(t, header, version, ExtraV1ECDSAScheme, 1, 2, 3, 4, 5)
+		e, err := ParseExtra(header.Extra)
+		require.NoError(t, err)
+		require.Equal(t, v.addrs, e.Addresses)
+		rebuilt, err := BuildExtra(e)
+		require.NoError(t, err)
+		require.Equal(t, header.Extra, rebuilt)
+	}
+	for _, version := range []byte{ExtraV1, ExtraV2} {
+		k.seal(t, header, version)
+		e, err := ParseExtra(header.Extra)
+		require.NoError(t, err)
+		require.Equal(t, true, e.GlobalKey.Equal(&k.pub))
+		rebuilt, err := BuildExtra(e)
+		require.NoError(t, err)
+		require.Equal(t, header.Extra, rebuilt)
+	}
+
+	// Custom scheme is used by the verifier
+	parent := newTestGenesis(testSchemeCommitment)
+	current := nextTestHeader(parent, testSchemeCommitment)
+	current.Extra = []byte{testExtraVersion}
+	hash, err := SealHash(DefaultChainConfig, current)
+	require.NoError(t, err)
+	current.Extra = append(current.Extra, hash[:]...)
+	require.Equal(t, true, VerifyUpdateHeaderWithConfig(testConfig, parent, current))
+	current.Extra[1] ^= 1
+	require.Equal(t, false, VerifyUpdateHeaderWithConfig(testConfig, parent, current))
+
+	require.Panics(t, func() { RegisterExtraVersion(testExtraVersion, testScheme{}) })
+	require.Panics(t, func() { RegisterExtraScheme(ExtraV2, ExtraV1ECDSAScheme, testScheme{}) })
+	require.Panics(t, func() { RegisterExtraScheme(ExtraV0, 1, testScheme{}) })
+
+	for _, extra := range [][]byte{nil, {3}, {ExtraV2}, {ExtraV2, 2}} {
+		_, err := LookupExtraScheme(extra)
+		require.Error(t, err)
+	}
+	_, err = ParseExtra([]byte{ExtraV2, ExtraV1ThresholdScheme})
+	require.Error(t, err)
+}
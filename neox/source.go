@@ -0,0 +1,307 @@
+package verifier
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// HeaderSource provides headers of a Neo X network.
+type HeaderSource interface {
+	// Height returns the number of the latest available header.
+	Height(ctx context.Context) (uint64, error)
+	// Headers returns count consecutive headers starting at number from.
+	Headers(ctx context.Context, from uint64, count int) ([]*types.Header, error)
+}
+
+// ErrHeaderNotFound is returned by sources for headers they don't have.
+var ErrHeaderNotFound = errors.New("header not found")
+
+// RPCSource is a HeaderSource backed by the eth_blockNumber and eth_getBlockByNumber
+// methods of a Neo X JSON-RPC node.
+type RPCSource struct {
+	client *rpc.Client
+	// Retries is the number of retries of a request failed by transport errors,
+	// RetryDelay is the delay before the first retry and doubles after it.
+	Retries    int
+	RetryDelay time.Duration
+	// BatchSize is the maximum number of calls in a batch request.
+	BatchSize int
+}
+
+// DialRPCSource connects RPCSource with default settings to endpoint.
+func DialRPCSource(ctx context.Context, endpoint string) (*RPCSource, error) {
+	client, err := rpc.DialContext(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	return NewRPCSource(client), nil
+}
+
+// NewRPCSource returns RPCSource with default settings using client.
+func NewRPCSource(client *rpc.Client) *RPCSource {
+	return &RPCSource{
+		client:     client,
+		Retries:    3,
+		RetryDelay: time.Second,
+		BatchSize:  100,
+	}
+}
+
+// Close closes the underlying client.
+func (s *RPCSource) Close() {
+	s.client.Close()
+}
+
+func (s *RPCSource) Height(ctx context.Context) (uint64, error) {
+	var height hexutil.Uint64
+	batch := []rpc.BatchElem{{Method: "eth_blockNumber", Result: &height}}
+	if err := s.call(ctx, batch); err != nil {
+		return 0, err
+	}
+	if batch[0].Error != nil {
+		return 0, batch[0].Error
+	}
+	return uint64(height), nil
+}
+
+func (s *RPCSource) Headers(ctx context.Context, from uint64, count int) ([]*types.Header, error) {
+	if count < 0 {
+		return nil, fmt.Errorf("negative count %d", count)
+	}
+	headers := make([]*types.Header, 0, count)
+	batchSize := max(s.BatchSize, 1)
+	for len(headers) < count {
+		batch := make([]rpc.BatchElem, min(batchSize, count-len(headers)))
+		results := make([]json.RawMessage, len(batch))
+		for i := range batch {
+			number := from + uint64(len(headers)+i)
+			batch[i] = rpc.BatchElem{
+				Method: "eth_getBlockByNumber",
+				Args:   []any{hexutil.Uint64(number), false},
+				Result: &results[i],
+			}
+		}
+		if err := s.call(ctx, batch); err != nil {
+			return nil, err
+		}
+		for i := range batch {
+			number := from + uint64(len(headers))
+			if batch[i].Error != nil {
+				return nil, fmt.Errorf("header %d: %w", number, batch[i].Error)
+			}
+			if len(results[i]) == 0 || string(results[i]) == "null" {
+				return nil, fmt.Errorf("header %d: %w", number, ErrHeaderNotFound)
+			}
+			header := new(types.Header)
+			if err := header.UnmarshalJSON(results[i]); err != nil {
+				return nil, fmt.Errorf("header %d: %w", number, err)
+			}
+			if header.Number.Uint64() != number {
+				return nil, fmt.Errorf("header %d: unexpected number %d", number, header.Number)
+			}
+			headers = append(headers, header)
+		}
+	}
+	return headers, nil
+}
+
+// call sends batch with retries.
+func (s *RPCSource) call(ctx context.Context, batch []rpc.BatchElem) error {
+	delay := s.RetryDelay
+	for attempt := 0; ; attempt++ {
+		err := s.client.BatchCallContext(ctx, batch)
+		if err == nil || attempt >= s.Retries || ctx.Err() != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+// FileSource is a HeaderSource of headers recorded in files.
+type FileSource struct {
+	headers map[uint64]*types.Header
+	tail    uint64
+	height  uint64
+}
+
+// NewFileSource loads headers from JSON array or newline-delimited JSON files, JSON-RPC
+// responses are unwrapped. Directories in paths are read file by file and "-" is stdin.
+func NewFileSource(paths ...string) (*FileSource, error) {
+	s := &FileSource{headers: make(map[uint64]*types.Header)}
+	for _, path := range paths {
+		if path == "-" {
+			if err := s.load(os.Stdin); err != nil {
+				return nil, fmt.Errorf("stdin: %w", err)
+			}
+			continue
+		}
+		files, err := recordedFiles(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			if err := s.loadFile(file); err != nil {
+				return nil, fmt.Errorf("%s: %w", file, err)
+			}
+		}
+	}
+	if len(s.headers) == 0 {
+		return nil, errors.New("no headers")
+	}
+	return s, nil
+}
+
+func (s *FileSource) loadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return s.load(f)
+}
+
+func (s *FileSource) load(r io.Reader) error {
+	raws, err := readRecordedHeaders(r)
+	if err != nil {
+		return err
+	}
+	for _, raw := range raws {
+		header := new(types.Header)
+		if err := header.UnmarshalJSON(raw); err != nil {
+			return err
+		}
+		number := header.Number.Uint64()
+		if len(s.headers) == 0 {
+			s.tail, s.height = number, number
+		}
+		if known, ok := s.headers[number]; ok && known.Hash() != header.Hash() {
+			return fmt.Errorf("header %d: conflicting hashes", number)
+		}
+		s.headers[number] = header
+		s.tail = min(s.tail, number)
+		s.height = max(s.height, number)
+	}
+	return nil
+}
+
+// Tail returns the number of the earliest header.
+func (s *FileSource) Tail(ctx context.Context) (uint64, error) {
+	return s.tail, nil
+}
+
+func (s *FileSource) Height(ctx context.Context) (uint64, error) {
+	return s.height, nil
+}
+
+func (s *FileSource) Headers(ctx context.Context, from uint64, count int) ([]*types.Header, error) {
+	if count < 0 {
+		return nil, fmt.Errorf("negative count %d", count)
+	}
+	headers := make([]*types.Header, count)
+	for i := range headers {
+		header, ok := s.headers[from+uint64(i)]
+		if !ok {
+			return nil, fmt.Errorf("header %d: %w", from+uint64(i), ErrHeaderNotFound)
+		}
+		headers[i] = header
+	}
+	return headers, nil
+}
+
+// recordedFiles returns path or the files of directory path.
+func recordedFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			files = append(files, filepath.Join(path, entry.Name()))
+		}
+	}
+	return files, nil
+}
+
+type recordedResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// readRecordedHeaders decodes a JSON array or a stream of JSON values, unwrapping
+// JSON-RPC responses.
+func readRecordedHeaders(r io.Reader) ([]json.RawMessage, error) {
+	br := bufio.NewReader(r)
+	var values []json.RawMessage
+	dec := json.NewDecoder(br)
+	for {
+		b, err := br.Peek(1)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil, errors.New("empty input")
+			}
+			return nil, err
+		}
+		if !bytes.ContainsAny(b, " \t\r\n") {
+			break
+		}
+		br.ReadByte()
+	}
+	if b, _ := br.Peek(1); b[0] == '[' {
+		if err := dec.Decode(&values); err != nil {
+			return nil, err
+		}
+	} else {
+		for {
+			var value json.RawMessage
+			err := dec.Decode(&value)
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+		}
+	}
+	for i := range values {
+		var resp recordedResponse
+		if err := json.Unmarshal(values[i], &resp); err != nil {
+			return nil, err
+		}
+		if resp.Error != nil {
+			return nil, fmt.Errorf("RPC error %d: %s", resp.Error.Code, resp.Error.Message)
+		}
+		if resp.Result != nil {
+			values[i] = resp.Result
+		}
+	}
+	return values, nil
+}
@@ -0,0 +1,123 @@
+package verifier
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/txhsl/neox-dbft-verifier/rpctest"
+)
+
+func newTestRPCServer(t testing.TB) *rpctest.Server {
+	s, err := rpctest.NewServer([]json.RawMessage{
+		json.RawMessage(testV0ToV1Parent),
+		json.RawMessage(testV0ToV1Current),
+		json.RawMessage(testV0ToV1Next),
+	})
+	require.NoError(t, err)
+	t.Cleanup(s.Close)
+	return s
+}
+
+func TestRPCSource(t *testing.T) {
+	s := newTestRPCServer(t)
+	ctx := context.Background()
+	src, err := DialRPCSource(ctx, s.URL)
+	require.NoError(t, err)
+	defer src.Close()
+	src.RetryDelay = time.Millisecond
+
+	height, err := src.Height(ctx)
+	require.NoError(t, err)
+	require.Equal(t, uint64(0x1fdc40), height)
+	headers, err := src.Headers(ctx, 0x1fdc3e, 3)
+	require.NoError(t, err)
+	require.Equal(t, 3, len(headers))
+	for i := 1; i < len(headers); i++ {
+		require.NoError(t, CheckUpdateHeader(DefaultChainConfig, headers[i-1], headers[i]))
+	}
+
+	// Batching
+	requests := s.Requests()
+	src.BatchSize = 2
+	_, err = src.Headers(ctx, 0x1fdc3e, 3)
+	require.NoError(t, err)
+	require.Equal(t, requests+2, s.Requests())
+
+	// Retries
+	s.FailNext(2)
+	_, err = src.Height(ctx)
+	require.NoError(t, err)
+	s.FailNext(4)
+	_, err = src.Height(ctx)
+	require.Error(t, err)
+
+	// Cancellation
+	s.FailNext(1)
+	src.RetryDelay = time.Hour
+	cctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	_, err = src.Height(cctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	_, err = src.Headers(ctx, 0x1fdc40, 2)
+	require.ErrorIs(t, err, ErrHeaderNotFound)
+	_, err = src.Headers(ctx, 0x1fdc40, -1)
+	require.Error(t, err)
+}
+
+func TestFileSource(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "headers.ndjson")
+	data := `{"jsonrpc":"2.0","id":1,"result":` + testV2Parent + "}\n" + testV2Current + "\n"
+	require.NoError(t, os.WriteFile(path, []byte(data), 0o644))
+	src, err := NewFileSource(path)
+	require.NoError(t, err)
+	height, err := src.Height(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, uint64(0x3aac82), height)
+	headers, err := src.Headers(context.Background(), 0x3aac81, 2)
+	require.NoError(t, err)
+	require.NoError(t, CheckUpdateHeader(DefaultChainConfig, headers[0], headers[1]))
+	_, err = src.Headers(context.Background(), 0x3aac81, 3)
+	require.ErrorIs(t, err, ErrHeaderNotFound)
+	_, err = src.Headers(context.Background(), 0x3aac81, -1)
+	require.Error(t, err)
+
+	// Directory and stdin
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "parent.json"), []byte(testV2Parent), 0o644))
+	stdin := filepath.Join(t.TempDir(), "stdin")
+	require.NoError(t, os.WriteFile(stdin, []byte(testV2Current), 0o644))
+	f, err := os.Open(stdin)
+	require.NoError(t, err)
+	defer f.Close()
+	defer func(stdin *os.File) { os.Stdin = stdin }(os.Stdin)
+	os.Stdin = f
+	src, err = NewFileSource(dir, "-")
+	require.NoError(t, err)
+	tail, err := src.Tail(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, uint64(0x3aac81), tail)
+	headers, err = src.Headers(context.Background(), 0x3aac81, 2)
+	require.NoError(t, err)
+	require.NoError(t, CheckUpdateHeader(DefaultChainConfig, headers[0], headers[1]))
+
+	require.NoError(t, os.WriteFile(path, []byte("["+testV2Parent+","+testV2Current+"]"), 0o644))
+	_, err = NewFileSource(path)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, []byte("["+testV2Parent+","+testV2Parent+"]"), 0o644))
+	_, err = NewFileSource(path)
+	require.NoError(t, err)
+	conflicting := strings.Replace(testV2Parent, `"gasUsed": "0x0"`, `"gasUsed": "0x1"`, 1)
+	require.NoError(t, os.WriteFile(path, []byte("["+testV2Parent+","+conflicting+"]"), 0o644))
+	_, err = NewFileSource(path)
+	require.ErrorContains(t, err, "header 3845249: conflicting hashes")
+	require.NoError(t, os.WriteFile(path, []byte(`{"jsonrpc":"2.0","id":1,"error":{"code":-32000,"message":"oops"}}`), 0o644))
+	_, err = NewFileSource(path)
+	require.Error(t, err)
+}
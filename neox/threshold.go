@@ -0,0 +1,114 @@
+package verifier
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// PartialSignature is the share of a validator in the threshold signature of a block.
+type PartialSignature struct {
+	// Index is the x-coordinate of the share, i.e. the validator index plus one.
+	Index     uint64
+	Signature bls12381.G2Affine
+}
+
+// VerifyPartialSig checks a partial signature of seal data against the share public
+// key of its validator.
+func VerifyPartialSig(sealData []byte, sig *bls12381.G2Affine, shareKey *bls12381.G1Affine) bool {
+	hash, err := bls12381.HashToG2(sealData, BLSDomain)
+	if err != nil {
+		return false
+	}
+	return verifyBLSSig(hash, sig, shareKey)
+}
+
+// AggregatePartialSigs interpolates partial signatures at zero, the result is the
+// threshold signature if there are at least threshold valid partials.
+func AggregatePartialSigs(partials []PartialSignature) (*bls12381.G2Affine, error) {
+	if len(partials) == 0 {
+		return nil, errors.New("no partial signatures")
+	}
+	xs := make([]fr.Element, len(partials))
+	seen := make(map[uint64]bool, len(partials))
+	for i := range partials {
+		if partials[i].Index == 0 || seen[partials[i].Index] {
+			return nil, fmt.Errorf("invalid share index %d", partials[i].Index)
+		}
+		seen[partials[i].Index] = true
+		xs[i].SetUint64(partials[i].Index)
+	}
+	points := make([]bls12381.G2Affine, len(partials))
+	for i := range partials {
+		points[i] = partials[i].Signature
+	}
+	sig := new(bls12381.G2Affine)
+	if _, err := sig.MultiExp(points, lagrangeAtZero(xs), ecc.MultiExpConfig{}); err != nil {
+		return nil, err
+	}
+	return sig, nil
+}
+
+// CombinePartialSigs verifies every partial against its share key, aggregates the
+// first threshold valid ones of distinct indexes and checks the result under the
+// global key. It returns the aggregated signature and the indexes of partials that
+// failed verification.
+func CombinePartialSigs(sealData []byte, partials []PartialSignature, shareKeys map[uint64]*bls12381.G1Affine, globalKey *bls12381.G1Affine, threshold int) (*bls12381.G2Affine, []uint64, error) {
+	hash, err := bls12381.HashToG2(sealData, BLSDomain)
+	if err != nil {
+		return nil, nil, err
+	}
+	var (
+		valid []PartialSignature
+		bad   []uint64
+		seen  = make(map[uint64]bool, len(partials))
+	)
+	for i := range partials {
+		key, ok := shareKeys[partials[i].Index]
+		if !ok || !verifyBLSSig(hash, &partials[i].Signature, key) {
+			bad = append(bad, partials[i].Index)
+			continue
+		}
+		// Valid partials of the same index are the same share
+		if seen[partials[i].Index] {
+			continue
+		}
+		seen[partials[i].Index] = true
+		if len(valid) < threshold {
+			valid = append(valid, partials[i])
+		}
+	}
+	if len(valid) < threshold {
+		return nil, bad, fmt.Errorf("%d valid partial signatures of %d required", len(valid), threshold)
+	}
+	sig, err := AggregatePartialSigs(valid)
+	if err != nil {
+		return nil, bad, err
+	}
+	if !verifyBLSSig(hash, sig, globalKey) {
+		return nil, bad, errors.New("aggregated signature is not valid under global key")
+	}
+	return sig, bad, nil
+}
+
+// lagrangeAtZero returns the Lagrange basis coefficients at zero for distinct xs.
+func lagrangeAtZero(xs []fr.Element) []fr.Element {
+	coeffs := make([]fr.Element, len(xs))
+	for i := range xs {
+		num, den := fr.One(), fr.One()
+		for j := range xs {
+			if i == j {
+				continue
+			}
+			var diff fr.Element
+			diff.Sub(&xs[j], &xs[i])
+			num.Mul(&num, &xs[j])
+			den.Mul(&den, &diff)
+		}
+		coeffs[i].Div(&num, &den)
+	}
+	return coeffs
+}
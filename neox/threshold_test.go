@@ -0,0 +1,79 @@
+package verifier
+
+import (
+	"math/big"
+	"testing"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCombinePartialSigs(t *testing.T) {
+	// Shares of 5-of-7 key
+	coeffs := make([]fr.Element, 5)
+	for i := range coeffs {
+		_, err := coeffs[i].SetRandom()
+		require.NoError(t, err)
+	}
+	_, _, g1, _ := bls12381.Generators()
+	var globalKey bls12381.G1Affine
+	globalKey.ScalarMultiplication(&g1, coeffs[0].BigInt(new(big.Int)))
+
+	parent := newTestGenesis((&testThresholdKey{pub: globalKey}).commitment())
+	header := nextTestHeader(parent, parent.MixDigest)
+	header.Extra = append([]byte{ExtraV2, ExtraV1ThresholdScheme}, make([]byte, 32)...)
+	data, err := SealData(DefaultChainConfig, header)
+	require.NoError(t, err)
+	hash, err := bls12381.HashToG2(data, BLSDomain)
+	require.NoError(t, err)
+
+	shareKeys := make(map[uint64]*bls12381.G1Affine)
+	partials := make([]PartialSignature, 7)
+	for i := range partials {
+		var share, x fr.Element
+		x.SetUint64(uint64(i + 1))
+		for j := len(coeffs) - 1; j >= 0; j-- {
+			share.Mul(&share, &x)
+			share.Add(&share, &coeffs[j])
+		}
+		key := new(bls12381.G1Affine).ScalarMultiplication(&g1, share.BigInt(new(big.Int)))
+		shareKeys[uint64(i+1)] = key
+		partials[i].Index = uint64(i + 1)
+		partials[i].Signature.ScalarMultiplication(&hash, share.BigInt(new(big.Int)))
+		require.Equal(t, true, VerifyPartialSig(data, &partials[i].Signature, key))
+	}
+	// Validator 2 sends a bad share
+	partials[1].Signature.Neg(&partials[1].Signature)
+	require.Equal(t, false, VerifyPartialSig(data, &partials[1].Signature, shareKeys[2]))
+
+	sig, bad, err := CombinePartialSigs(data, partials, shareKeys, &globalKey, 5)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{2}, bad)
+
+	// Aggregated signature seals the block
+	header.Extra, err = BuildExtra(&Extra{Version: ExtraV2, Scheme: ExtraV1ThresholdScheme, GlobalKey: &globalKey, Signature: sig})
+	require.NoError(t, err)
+	require.Equal(t, true, VerifyUpdateHeaderWithConfig(testConfig, parent, header))
+
+	// Bad share makes plain aggregation invalid
+	sig, err = AggregatePartialSigs(partials[:5])
+	require.NoError(t, err)
+	require.Equal(t, false, verifyBLSSig(hash, sig, &globalKey))
+
+	_, bad, err = CombinePartialSigs(data, partials[1:5], shareKeys, &globalKey, 5)
+	require.Error(t, err)
+	require.Equal(t, []uint64{2}, bad)
+	_, err = AggregatePartialSigs([]PartialSignature{partials[0], partials[0]})
+	require.Error(t, err)
+
+	// Duplicate partials don't count towards the threshold
+	dup := []PartialSignature{partials[0], partials[0], partials[2], partials[3], partials[4]}
+	_, bad, err = CombinePartialSigs(data, dup, shareKeys, &globalKey, 5)
+	require.Error(t, err)
+	require.Empty(t, bad)
+	sig, bad, err = CombinePartialSigs(data, append(dup, partials[5]), shareKeys, &globalKey, 5)
+	require.NoError(t, err)
+	require.Empty(t, bad)
+	require.Equal(t, true, verifyBLSSig(hash, sig, &globalKey))
+}
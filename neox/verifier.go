@@ -3,6 +3,7 @@ package verifier
 import (
 	"bytes"
 	"errors"
+	"fmt"
 	"math/big"
 	"sort"
 
@@ -12,7 +13,6 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/rlp"
-	"golang.org/x/crypto/sha3"
 )
 
 const (
@@ -48,139 +48,78 @@ var (
 	BLSDomain = []byte("BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_")
 )
 
+var (
+	ErrParentHash = errors.New("parent hash mismatch")
+	ErrNumber     = errors.New("unexpected number")
+	ErrTime       = errors.New("time is not increasing")
+	ErrExtra      = errors.New("malformed extra")
+	ErrConsensus  = errors.New("extra doesn't match parent mix digest")
+	ErrSealData   = errors.New("unexpected header fields")
+	ErrSignature  = errors.New("invalid seal signature")
+)
+
+// VerifyUpdateHeader verifies current against parent without a fork schedule, the
+// optional BaseFee and WithdrawalsHash are hashed if set.
 func VerifyUpdateHeader(parent, current *types.Header) bool {
+	return CheckUpdateHeader(nil, parent, current) == nil
+}
+
+// VerifyUpdateHeaderWithConfig is VerifyUpdateHeader for a network with the given fork
+// schedule, optional header fields must match the forks active at the header time.
+func VerifyUpdateHeaderWithConfig(config *ChainConfig, parent, current *types.Header) bool {
+	return CheckUpdateHeader(config, parent, current) == nil
+}
+
+// CheckUpdateHeader is VerifyUpdateHeaderWithConfig returning the reason of the
+// failure, details are wrapped into one of the Err* values. A nil config checks like
+// VerifyUpdateHeader.
+func CheckUpdateHeader(config *ChainConfig, parent, current *types.Header) error {
 	// Check basic
 	if current.ParentHash != parent.Hash() {
-		return false
+		return ErrParentHash
 	}
 	if current.Number.Cmp(new(big.Int).Add(parent.Number, big.NewInt(1))) != 0 {
-		return false
+		return ErrNumber
 	}
 	if current.Time <= parent.Time {
-		return false
+		return ErrTime
 	}
-	expectConsensus := parent.MixDigest
-	if len(current.Extra) < 1 {
-		return false
+	if err := config.CheckExtra(parent, current); err != nil {
+		return fmt.Errorf("%w: %w", ErrExtra, err)
 	}
-	switch current.Extra[0] {
-	case ExtraV0:
-		// Check format
-		if len(current.Extra) != HashableExtraV0Len+7*common.AddressLength+5*crypto.SignatureLength {
-			return false
-		}
-		// Get CNs and sigs
-		addrBytes := current.Extra[HashableExtraV0Len : HashableExtraV0Len+7*common.AddressLength]
-		sigBytes := current.Extra[HashableExtraV0Len+7*common.AddressLength:]
-		addrs := make([]common.Address, 7)
-		for i := range addrs {
-			copy(addrs[i][:], addrBytes[i*common.AddressLength:(i+1)*common.AddressLength])
-		}
-		sigs := make([][]byte, 5)
-		for i := range sigs {
-			sigs[i] = sigBytes[i*crypto.SignatureLength : (i+1)*crypto.SignatureLength]
-		}
-		// Verify CNs
-		exactConsensus := common.BytesToHash(crypto.Keccak256(addrBytes))
-		if exactConsensus != expectConsensus {
-			return false
-		}
-		// Get seal hash
-		data, err := encodeSigHeader(current)
-		if err != nil {
-			return false
-		}
-		hasher := sha3.NewLegacyKeccak256()
-		hasher.Write(data)
-		// Verify sigs
-		return verifyMultiSigs(hasher.Sum(nil), sigs, addrs)
-	case ExtraV1, ExtraV2:
-		if len(current.Extra) < 2 {
-			return false
-		}
-		switch current.Extra[1] {
-		case ExtraV1ECDSAScheme:
-			// Check format
-			if len(current.Extra) != HashableExtraV1Len+7*common.AddressLength+5*crypto.SignatureLength {
-				return false
-			}
-			// Get CNs and sigs
-			addrBytes := current.Extra[HashableExtraV1Len : HashableExtraV1Len+7*common.AddressLength]
-			sigBytes := current.Extra[HashableExtraV1Len+7*common.AddressLength:]
-			addrs := make([]common.Address, 7)
-			for i := range addrs {
-				copy(addrs[i][:], addrBytes[i*common.AddressLength:(i+1)*common.AddressLength])
-			}
-			sigs := make([][]byte, 5)
-			for i := range sigs {
-				sigs[i] = sigBytes[i*crypto.SignatureLength : (i+1)*crypto.SignatureLength]
-			}
-			// Verify CNs
-			exactConsensus := common.BytesToHash(crypto.Keccak256(addrBytes))
-			if exactConsensus != expectConsensus {
-				return false
-			}
-			// Get seal hash
-			data, err := encodeSigHeader(current)
-			if err != nil {
-				return false
-			}
-			hasher := sha3.NewLegacyKeccak256()
-			hasher.Write(data)
-			// Verify sigs
-			return verifyMultiSigs(hasher.Sum(nil), sigs, addrs)
-		case ExtraV1ThresholdScheme:
-			// Check format
-			if len(current.Extra) != HashableExtraV1Len+BLSPublicKeyLen+BLSSignatureLen {
-				return false
-			}
-			// Get global public key and sig
-			pubBytes := current.Extra[HashableExtraV1Len : HashableExtraV1Len+BLSPublicKeyLen]
-			sigBytes := current.Extra[HashableExtraV1Len+BLSPublicKeyLen : HashableExtraV1Len+BLSPublicKeyLen+BLSSignatureLen]
-			pk := new(bls12381.G1Affine)
-			_, err := pk.SetBytes(pubBytes)
-			if err != nil {
-				return false
-			}
-			sig := new(bls12381.G2Affine)
-			_, err = sig.SetBytes(sigBytes)
-			if err != nil {
-				return false
-			}
-			// Verify global public key
-			exactConsensus := common.BytesToHash(crypto.Keccak256(pubBytes))
-			if exactConsensus != expectConsensus {
-				return false
-			}
-			// Get seal hash
-			data, err := encodeSigHeader(current)
-			if err != nil {
-				return false
-			}
-			hash, _ := bls12381.HashToG2(data, BLSDomain)
-			// Negate the sig in V1
-			if current.Extra[0] == ExtraV1 {
-				sig.Neg(sig)
-			}
-			// Verify sig
-			return verifyBLSSig(hash, sig, pk)
-		default:
-			return false
-		}
-	default:
-		return false
+	scheme, err := LookupExtraScheme(current.Extra)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrExtra, err)
+	}
+	// Check format
+	extra, err := scheme.Parse(current.Extra)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrExtra, err)
+	}
+	// Verify CNs
+	if scheme.Commitment(extra) != parent.MixDigest {
+		return ErrConsensus
+	}
+	// Get seal data
+	data, err := encodeSigHeader(config, current)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrSealData, err)
+	}
+	// Verify sigs
+	if !scheme.Verify(extra, data) {
+		return ErrSignature
 	}
+	return nil
 }
 
-func encodeSigHeader(header *types.Header) ([]byte, error) {
-	var hashableExtraLen int
-	switch v := header.Extra[0]; v {
-	case ExtraV0:
-		hashableExtraLen = HashableExtraV0Len
-	case ExtraV1, ExtraV2:
-		hashableExtraLen = HashableExtraV1Len
-	default:
-		return nil, errors.New("unexpected extra version")
+func encodeSigHeader(config *ChainConfig, header *types.Header) ([]byte, error) {
+	scheme, err := LookupExtraScheme(header.Extra)
+	if err != nil {
+		return nil, err
+	}
+	hashableExtraLen := scheme.HashableLen()
+	if len(header.Extra) < hashableExtraLen {
+		return nil, errors.New("extra is too short")
 	}
 	enc := []interface{}{
 		header.ParentHash,
@@ -195,31 +134,55 @@ func encodeSigHeader(header *types.Header) ([]byte, error) {
 		header.GasLimit,
 		header.GasUsed,
 		header.Time,
-		header.Extra[:hashableExtraLen], // Yes, this will panic if extra is too short
+		header.Extra[:hashableExtraLen],
 		header.MixDigest,
 		header.Nonce,
 	}
-	if header.BaseFee != nil {
-		enc = append(enc, header.BaseFee)
+	// Without a schedule optional fields are hashed if set
+	if config == nil {
+		if header.BaseFee != nil {
+			enc = append(enc, header.BaseFee)
+		}
+		if header.WithdrawalsHash != nil {
+			enc = append(enc, header.WithdrawalsHash)
+		}
+		return rlp.EncodeToBytes(enc)
+	}
+	// Optional fields follow the fork schedule in the canonical header order
+	if header.BaseFee == nil {
+		return nil, errors.New("missing base fee")
+	}
+	enc = append(enc, header.BaseFee)
+	if config.IsShanghai(header.Time) != (header.WithdrawalsHash != nil) {
+		return nil, errors.New("unexpected withdrawals hash")
 	}
 	if header.WithdrawalsHash != nil {
 		enc = append(enc, header.WithdrawalsHash)
 	}
+	cancun := config.IsCancun(header.Time)
+	if cancun != (header.BlobGasUsed != nil) || cancun != (header.ExcessBlobGas != nil) || cancun != (header.ParentBeaconRoot != nil) {
+		return nil, errors.New("unexpected blob gas or parent beacon root")
+	}
+	if cancun {
+		enc = append(enc, header.BlobGasUsed, header.ExcessBlobGas, header.ParentBeaconRoot)
+	}
+	if config.IsPrague(header.Time) != (header.RequestsHash != nil) {
+		return nil, errors.New("unexpected requests hash")
+	}
+	if header.RequestsHash != nil {
+		enc = append(enc, header.RequestsHash)
+	}
 	return rlp.EncodeToBytes(enc)
 }
 
 func verifyMultiSigs(hash []byte, sigs [][]byte, addrs []common.Address) bool {
 	signers := make([]common.Address, len(sigs))
 	for i := range signers {
-		btcsig := make([]byte, crypto.SignatureLength)
-		btcsig[0] = sigs[i][64] + 27
-		copy(btcsig[1:], sigs[i])
-		pub, _, err := btc_ecdsa.RecoverCompact(btcsig, hash)
+		var err error
+		signers[i], err = recoverSigner(hash, sigs[i])
 		if err != nil {
 			return false
 		}
-		pubBytes := pub.SerializeUncompressed()
-		signers[i] = common.BytesToAddress(crypto.Keccak256(pubBytes[1:])[12:])
 	}
 	sort.Slice(addrs, func(i, j int) bool {
 		return bytes.Compare(addrs[i][:], addrs[j][:]) < 0
@@ -243,6 +206,19 @@ func verifyMultiSigs(hash []byte, sigs [][]byte, addrs []common.Address) bool {
 	return true
 }
 
+// recoverSigner returns the address of the [R || S || V] signature of hash.
+func recoverSigner(hash []byte, sig []byte) (common.Address, error) {
+	btcsig := make([]byte, crypto.SignatureLength)
+	btcsig[0] = sig[64] + 27
+	copy(btcsig[1:], sig)
+	pub, _, err := btc_ecdsa.RecoverCompact(btcsig, hash)
+	if err != nil {
+		return common.Address{}, err
+	}
+	pubBytes := pub.SerializeUncompressed()
+	return common.BytesToAddress(crypto.Keccak256(pubBytes[1:])[12:]), nil
+}
+
 func verifyBLSSig(hash bls12381.G2Affine, sig *bls12381.G2Affine, pub *bls12381.G1Affine) bool {
 	_, _, g1, _ := bls12381.Generators()
 	g1.Neg(&g1)
@@ -0,0 +1,67 @@
+package verifier
+
+import (
+	"errors"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ValidatorsCount is the default number of consensus nodes, for headers that don't
+// reveal the validator set if the count isn't known from a verified consensus list,
+// see ConsensusFromProof.
+const ValidatorsCount = 7
+
+var (
+	// ErrPrimaryIndex is returned for headers with Nonce out of the validator set.
+	ErrPrimaryIndex = errors.New("primary index out of validator set")
+	// ErrRound is returned for headers with Difficulty other than DiffInTurn and
+	// DiffNoTurn or not matching the view.
+	ErrRound = errors.New("difficulty doesn't match view")
+)
+
+// HeaderValidatorsCount returns the number of addresses in the extra of header for
+// ExtraV0 and ECDSA scheme, or n for headers that don't reveal the validator set.
+func HeaderValidatorsCount(header *types.Header, n int) int {
+	extra, err := ParseExtra(header.Extra)
+	if err != nil || len(extra.Addresses) == 0 {
+		return n
+	}
+	return len(extra.Addresses)
+}
+
+// Primary returns the index of the primary validator of view for block number of n
+// validators, the header Nonce.
+func Primary(number uint64, view, n int) int {
+	first := int(number % uint64(n))
+	return ((first-view)%n + n) % n
+}
+
+// View returns the view header was accepted in by n validators. The round is encoded
+// in the header as the primary index in Nonce and DiffInTurn or DiffNoTurn in
+// Difficulty for view 0 and later views. Views are known modulo n, a multiple of it
+// that isn't 0 is returned as n.
+func View(header *types.Header, n int) (int, error) {
+	primary := header.Nonce.Uint64()
+	if n <= 0 || primary >= uint64(n) {
+		return 0, ErrPrimaryIndex
+	}
+	if header.Difficulty == nil || !header.Difficulty.IsUint64() {
+		return 0, ErrRound
+	}
+	first := Primary(header.Number.Uint64(), 0, n)
+	view := ((first-int(primary))%n + n) % n
+	switch header.Difficulty.Uint64() {
+	case DiffInTurn:
+		if view != 0 {
+			return 0, ErrRound
+		}
+		return 0, nil
+	case DiffNoTurn:
+		if view == 0 {
+			return n, nil
+		}
+		return view, nil
+	default:
+		return 0, ErrRound
+	}
+}
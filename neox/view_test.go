@@ -0,0 +1,64 @@
+package verifier
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestView(t *testing.T) {
+	for _, data := range []string{testV0Current, testV1Parent, testV1Current, testV0ToV1Parent, testV0ToV1Current, testV0ToV1Next, testV2Parent, testV2Current} {
+		header := decodeTestHeader(t, data)
+		view, err := View(header, HeaderValidatorsCount(header, ValidatorsCount))
+		require.NoError(t, err)
+		require.Equal(t, 0, view)
+	}
+	// Block 17 was proposed by the primary of view 1
+	view, err := View(decodeTestHeader(t, testV0Parent), ValidatorsCount)
+	require.NoError(t, err)
+	require.Equal(t, 1, view)
+
+	header := &types.Header{Number: big.NewInt(15), Difficulty: big.NewInt(DiffNoTurn)}
+	for view := 1; view < ValidatorsCount; view++ {
+		header.Nonce = types.EncodeNonce(uint64(Primary(15, view, ValidatorsCount)))
+		v, err := View(header, ValidatorsCount)
+		require.NoError(t, err)
+		require.Equal(t, view, v)
+	}
+	require.Equal(t, 1, Primary(15, 0, ValidatorsCount))
+	require.Equal(t, 0, Primary(15, 1, ValidatorsCount))
+	require.Equal(t, 6, Primary(15, 2, ValidatorsCount))
+	require.Equal(t, 2, Primary(15, 1, 4))
+
+	// View 7 has the primary of view 0
+	header.Nonce = types.EncodeNonce(1)
+	view, err = View(header, ValidatorsCount)
+	require.NoError(t, err)
+	require.Equal(t, ValidatorsCount, view)
+	// Four validators
+	view, err = View(header, 4)
+	require.NoError(t, err)
+	require.Equal(t, 2, view)
+
+	header.Difficulty = big.NewInt(DiffInTurn)
+	header.Nonce = types.EncodeNonce(2)
+	_, err = View(header, ValidatorsCount)
+	require.ErrorIs(t, err, ErrRound)
+	header.Difficulty = big.NewInt(3)
+	_, err = View(header, ValidatorsCount)
+	require.ErrorIs(t, err, ErrRound)
+	header.Nonce = types.EncodeNonce(ValidatorsCount)
+	_, err = View(header, ValidatorsCount)
+	require.ErrorIs(t, err, ErrPrimaryIndex)
+	header.Nonce = types.EncodeNonce(4)
+	_, err = View(header, 4)
+	require.ErrorIs(t, err, ErrPrimaryIndex)
+	_, err = View(header, 0)
+	require.ErrorIs(t, err, ErrPrimaryIndex)
+
+	// Validators count of the extra
+	require.Equal(t, 7, HeaderValidatorsCount(decodeTestHeader(t, testV0Parent), 4))
+	require.Equal(t, 4, HeaderValidatorsCount(header, 4))
+}
@@ -0,0 +1,50 @@
+// Package reason maps verification errors to machine-readable reasons, shared by the
+// API responses and the rejection metrics.
+package reason
+
+import (
+	"errors"
+
+	"github.com/txhsl/dbft-verifier/bundle"
+	n3 "github.com/txhsl/n3-dbft-verifier"
+	neox "github.com/txhsl/neox-dbft-verifier"
+)
+
+// Invalid is the reason of verification errors without a specific reason.
+const Invalid = "invalid"
+
+// reasons are the reasons of verification errors, checked in order.
+var reasons = []struct {
+	err    error
+	reason string
+}{
+	{n3.ErrPrevHash, "prev_hash_mismatch"},
+	{n3.ErrIndex, "unexpected_index"},
+	{n3.ErrTimestamp, "timestamp_not_increasing"},
+	{n3.ErrNextConsensus, "next_consensus_mismatch"},
+	{n3.ErrScript, "malformed_witness"},
+	{n3.ErrSignature, "invalid_signature"},
+	{neox.ErrParentHash, "parent_hash_mismatch"},
+	{neox.ErrNumber, "unexpected_number"},
+	{neox.ErrTime, "time_not_increasing"},
+	{neox.ErrExtra, "malformed_extra"},
+	{neox.ErrConsensus, "consensus_mismatch"},
+	{neox.ErrSealData, "unexpected_header_fields"},
+	{neox.ErrSignature, "invalid_signature"},
+	{n3.ErrPrimaryIndex, "primary_out_of_range"},
+	{neox.ErrPrimaryIndex, "primary_out_of_range"},
+	{neox.ErrRound, "round_mismatch"},
+	{bundle.ErrUntrustedAnchor, "untrusted_anchor"},
+	{bundle.ErrInvalidProof, "invalid_proof"},
+}
+
+// Of returns the reason of a verification error, Invalid for errors without a
+// specific reason.
+func Of(err error) string {
+	for _, r := range reasons {
+		if errors.Is(err, r.err) {
+			return r.reason
+		}
+	}
+	return Invalid
+}
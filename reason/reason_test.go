@@ -0,0 +1,19 @@
+package reason
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/txhsl/dbft-verifier/bundle"
+	n3 "github.com/txhsl/n3-dbft-verifier"
+	neox "github.com/txhsl/neox-dbft-verifier"
+)
+
+func TestOf(t *testing.T) {
+	require.Equal(t, "invalid_signature", Of(n3.ErrSignature))
+	require.Equal(t, "malformed_extra", Of(fmt.Errorf("%w: %w", neox.ErrExtra, errors.New("empty extra"))))
+	require.Equal(t, "invalid_proof", Of(fmt.Errorf("header 1: %w", bundle.ErrInvalidProof)))
+	require.Equal(t, Invalid, Of(errors.New("other")))
+}
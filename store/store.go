@@ -0,0 +1,167 @@
+// Package store persists verified headers of a chain by height.
+package store
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/ethdb/leveldb"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
+)
+
+var (
+	headKey      = []byte("H")
+	configKey    = []byte("C") // encoded chain configuration
+	headerPrefix = []byte("h") // headerPrefix + height (uint64 big endian) -> encoded header
+	hashPrefix   = []byte("n") // hashPrefix + hash -> height (uint64 big endian)
+)
+
+// ErrNotFound is returned for heights that are not stored.
+var ErrNotFound = errors.New("header not found")
+
+// Header is an encoded header with its hash.
+type Header struct {
+	Hash []byte
+	Data []byte
+}
+
+// Store is a header store, headers are opaque encoded headers of a single chain.
+type Store struct {
+	db ethdb.KeyValueStore
+}
+
+// Open opens or creates a LevelDB backed store at path.
+func Open(path string) (*Store, error) {
+	db, err := leveldb.New(path, 16, 16, "", false)
+	if err != nil {
+		return nil, err
+	}
+	return &Store{db: db}, nil
+}
+
+// NewMemory returns an in-memory store.
+func NewMemory() *Store {
+	return &Store{db: memorydb.New()}
+}
+
+// Close closes the underlying database.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Put stores consecutive headers starting at height from and makes the last one the
+// head, atomically.
+func (s *Store) Put(from uint64, headers []Header) error {
+	if len(headers) == 0 {
+		return nil
+	}
+	batch := s.db.NewBatch()
+	for i, header := range headers {
+		height := binary.BigEndian.AppendUint64(nil, from+uint64(i))
+		if err := batch.Put(headerKey(from+uint64(i)), header.Data); err != nil {
+			return err
+		}
+		if err := batch.Put(append(append([]byte{}, hashPrefix...), header.Hash...), height); err != nil {
+			return err
+		}
+	}
+	head := binary.BigEndian.AppendUint64(nil, from+uint64(len(headers)-1))
+	if err := batch.Put(headKey, head); err != nil {
+		return err
+	}
+	return batch.Write()
+}
+
+// Get returns the header at height.
+func (s *Store) Get(height uint64) ([]byte, error) {
+	data, err := s.db.Get(headerKey(height))
+	if err != nil {
+		if ok, _ := s.db.Has(headerKey(height)); !ok {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return data, nil
+}
+
+// GetByHash returns the height and the header with hash.
+func (s *Store) GetByHash(hash []byte) (uint64, []byte, error) {
+	key := append(append([]byte{}, hashPrefix...), hash...)
+	data, err := s.db.Get(key)
+	if err != nil {
+		if ok, _ := s.db.Has(key); !ok {
+			return 0, nil, ErrNotFound
+		}
+		return 0, nil, err
+	}
+	if len(data) != 8 {
+		return 0, nil, errors.New("malformed hash index")
+	}
+	height := binary.BigEndian.Uint64(data)
+	header, err := s.Get(height)
+	if err != nil {
+		return 0, nil, err
+	}
+	return height, header, nil
+}
+
+// Head returns the height and the header of the head, or ErrNotFound for an empty
+// store.
+func (s *Store) Head() (uint64, []byte, error) {
+	data, err := s.db.Get(headKey)
+	if err != nil {
+		if ok, _ := s.db.Has(headKey); !ok {
+			return 0, nil, ErrNotFound
+		}
+		return 0, nil, err
+	}
+	if len(data) != 8 {
+		return 0, nil, errors.New("malformed head")
+	}
+	height := binary.BigEndian.Uint64(data)
+	header, err := s.Get(height)
+	if err != nil {
+		return 0, nil, err
+	}
+	return height, header, nil
+}
+
+// Tail returns the height of the lowest stored header, or ErrNotFound for an empty
+// store.
+func (s *Store) Tail() (uint64, error) {
+	it := s.db.NewIterator(headerPrefix, nil)
+	defer it.Release()
+	if !it.Next() {
+		if err := it.Error(); err != nil {
+			return 0, err
+		}
+		return 0, ErrNotFound
+	}
+	if len(it.Key()) != len(headerPrefix)+8 {
+		return 0, errors.New("malformed header key")
+	}
+	return binary.BigEndian.Uint64(it.Key()[len(headerPrefix):]), nil
+}
+
+// Config returns the chain configuration the headers were verified with, or
+// ErrNotFound if it's not stored.
+func (s *Store) Config() ([]byte, error) {
+	data, err := s.db.Get(configKey)
+	if err != nil {
+		if ok, _ := s.db.Has(configKey); !ok {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return data, nil
+}
+
+// PutConfig stores the encoded chain configuration the headers are verified with.
+func (s *Store) PutConfig(data []byte) error {
+	return s.db.Put(configKey, data)
+}
+
+func headerKey(height uint64) []byte {
+	return binary.BigEndian.AppendUint64(append([]byte{}, headerPrefix...), height)
+}
@@ -0,0 +1,60 @@
+package store
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "headers")
+	s, err := Open(path)
+	require.NoError(t, err)
+	_, _, err = s.Head()
+	require.ErrorIs(t, err, ErrNotFound)
+	_, err = s.Tail()
+	require.ErrorIs(t, err, ErrNotFound)
+	_, err = s.Config()
+	require.ErrorIs(t, err, ErrNotFound)
+	require.NoError(t, s.PutConfig([]byte("config")))
+
+	require.NoError(t, s.Put(10, []Header{{Hash: []byte{0xa1}, Data: []byte{1}}, {Hash: []byte{0xa2}, Data: []byte{2}}, {Hash: []byte{0xa3}, Data: []byte{3}}}))
+	require.NoError(t, s.Put(13, nil))
+	height, head, err := s.Head()
+	require.NoError(t, err)
+	require.Equal(t, uint64(12), height)
+	require.Equal(t, []byte{3}, head)
+	require.NoError(t, s.Close())
+
+	// Reopen
+	s, err = Open(path)
+	require.NoError(t, err)
+	defer s.Close()
+	height, _, err = s.Head()
+	require.NoError(t, err)
+	require.Equal(t, uint64(12), height)
+	config, err := s.Config()
+	require.NoError(t, err)
+	require.Equal(t, []byte("config"), config)
+	header, err := s.Get(11)
+	require.NoError(t, err)
+	require.Equal(t, []byte{2}, header)
+	_, err = s.Get(13)
+	require.ErrorIs(t, err, ErrNotFound)
+	height, header, err = s.GetByHash([]byte{0xa2})
+	require.NoError(t, err)
+	require.Equal(t, uint64(11), height)
+	require.Equal(t, []byte{2}, header)
+	_, _, err = s.GetByHash([]byte{0xa4})
+	require.ErrorIs(t, err, ErrNotFound)
+	height, err = s.Tail()
+	require.NoError(t, err)
+	require.Equal(t, uint64(10), height)
+
+	m := NewMemory()
+	require.NoError(t, m.Put(0, []Header{{Hash: []byte{0xa1}, Data: []byte{1}}}))
+	height, _, err = m.Head()
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), height)
+}
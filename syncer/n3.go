@@ -0,0 +1,156 @@
+package syncer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/block"
+	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/txhsl/dbft-verifier/store"
+	n3 "github.com/txhsl/n3-dbft-verifier"
+)
+
+// N3Chain is Chain of N3, headers are stored in binary encoding.
+type N3Chain struct {
+	Client *n3.LightClient
+	Source n3.HeaderSource
+}
+
+// ResumeN3 returns a light client following the head of st, or trusted if st is empty,
+// that is stored then. The history of the client is rebuilt from the stored headers,
+// so its leaf indexes and roots are the ones of the client that stored them. It
+// returns ErrNetworkMismatch if st was synced with another network.
+func ResumeN3(st *store.Store, trusted *block.Header, network uint32) (*n3.LightClient, error) {
+	_, data, err := st.Head()
+	if errors.Is(err, store.ErrNotFound) {
+		if trusted == nil {
+			return nil, errors.New("empty store and no trusted header")
+		}
+		if err := SaveN3Network(st, network); err != nil {
+			return nil, err
+		}
+		header, err := encodeN3Header(trusted)
+		if err != nil {
+			return nil, err
+		}
+		if err := st.Put(uint64(trusted.Index), []store.Header{header}); err != nil {
+			return nil, err
+		}
+		return n3.NewLightClient(trusted, network), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := SaveN3Network(st, network); err != nil {
+		return nil, err
+	}
+	head, err := DecodeN3Header(data)
+	if err != nil {
+		return nil, err
+	}
+	history := n3.NewMMR()
+	err = replay(st, func(data []byte) error {
+		header, err := DecodeN3Header(data)
+		if err != nil {
+			return err
+		}
+		history.Append(header.Hash())
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return n3.ResumeLightClient(head, network, history), nil
+}
+
+// ErrNetworkMismatch is returned when a store was synced with another network.
+var ErrNetworkMismatch = errors.New("store was synced with another network")
+
+// LoadN3Network returns the network magic st was synced with, or store.ErrNotFound if
+// none is stored.
+func LoadN3Network(st *store.Store) (uint32, error) {
+	data, err := st.Config()
+	if err != nil {
+		return 0, err
+	}
+	var network uint32
+	if err := json.Unmarshal(data, &network); err != nil {
+		return 0, err
+	}
+	return network, nil
+}
+
+// SaveN3Network stores network as the network magic st is synced with, it returns
+// ErrNetworkMismatch if another one is stored.
+func SaveN3Network(st *store.Store, network uint32) error {
+	data, err := json.Marshal(network)
+	if err != nil {
+		return err
+	}
+	stored, err := st.Config()
+	switch {
+	case errors.Is(err, store.ErrNotFound):
+		return st.PutConfig(data)
+	case err != nil:
+		return err
+	case !bytes.Equal(stored, data):
+		return fmt.Errorf("%w: stored %s, got %d", ErrNetworkMismatch, stored, network)
+	}
+	return nil
+}
+
+// DecodeN3Header decodes a stored N3 header of a network without state roots in
+// headers.
+func DecodeN3Header(data []byte) (*block.Header, error) {
+	return DecodeN3HeaderStateRoot(data, false)
+}
+
+// DecodeN3HeaderStateRoot decodes a stored N3 header of a network with the given
+// StateRootInHeader setting.
+func DecodeN3HeaderStateRoot(data []byte, stateRootInHeader bool) (*block.Header, error) {
+	header := &block.Header{StateRootEnabled: stateRootInHeader}
+	r := io.NewBinReaderFromBuf(data)
+	header.DecodeBinary(r)
+	if r.Err == nil && r.Len() != 0 {
+		r.Err = errors.New("trailing data")
+	}
+	if r.Err != nil {
+		return nil, r.Err
+	}
+	return header, nil
+}
+
+// encodeN3Header returns the binary encoding of h, it's stored with the big endian
+// hash.
+func encodeN3Header(h *block.Header) (store.Header, error) {
+	w := io.NewBufBinWriter()
+	h.EncodeBinary(w.BinWriter)
+	if w.Err != nil {
+		return store.Header{}, w.Err
+	}
+	return store.Header{Hash: h.Hash().BytesBE(), Data: w.Bytes()}, nil
+}
+
+func (c *N3Chain) Head() uint64 {
+	return uint64(c.Client.Head().Index)
+}
+
+func (c *N3Chain) Tip(ctx context.Context) (uint64, error) {
+	height, err := c.Source.Height(ctx)
+	return uint64(height), err
+}
+
+func (c *N3Chain) Fetch(ctx context.Context, from uint64, count int) ([]*block.Header, error) {
+	return c.Source.Headers(ctx, uint32(from), count)
+}
+
+func (c *N3Chain) Update(h *block.Header, commit func() error) error {
+	return c.Client.UpdateWith(h, commit)
+}
+
+func (c *N3Chain) Encode(h *block.Header) (store.Header, error) {
+	return encodeN3Header(h)
+}
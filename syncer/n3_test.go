@@ -0,0 +1,74 @@
+package syncer
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/block"
+	"github.com/stretchr/testify/require"
+	"github.com/txhsl/dbft-verifier/store"
+	n3 "github.com/txhsl/n3-dbft-verifier"
+	n3rpctest "github.com/txhsl/n3-dbft-verifier/rpctest"
+)
+
+func TestN3Chain(t *testing.T) {
+	data, err := os.ReadFile("../bundle/testdata/n3_headers.json")
+	require.NoError(t, err)
+	var raws []json.RawMessage
+	require.NoError(t, json.Unmarshal(data, &raws))
+	server, err := n3rpctest.NewServer(raws)
+	require.NoError(t, err)
+	defer server.Close()
+	trusted := new(block.Header)
+	require.NoError(t, trusted.UnmarshalJSON(raws[0]))
+
+	st := store.NewMemory()
+	_, err = ResumeN3(st, nil, 860833102)
+	require.Error(t, err)
+	_, err = LoadN3Network(st)
+	require.ErrorIs(t, err, store.ErrNotFound)
+	client, err := ResumeN3(st, trusted, 860833102)
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- New[*block.Header](&N3Chain{Client: client, Source: n3.NewRPCSource(server.URL)}, st, Config{PollInterval: time.Millisecond}).Run(ctx)
+	}()
+	waitHead(t, st, 10000)
+	cancel()
+	require.ErrorIs(t, <-done, context.Canceled)
+	root := client.History().Root()
+	proof, err := client.History().Prove(1)
+	require.NoError(t, err)
+
+	// Resume with the history
+	client, err = ResumeN3(st, nil, 860833102)
+	require.NoError(t, err)
+	require.Equal(t, uint32(10000), client.Head().Index)
+	stored, err := st.Get(9999)
+	require.NoError(t, err)
+	header, err := DecodeN3Header(stored)
+	require.NoError(t, err)
+	require.Equal(t, trusted.Hash(), header.Hash())
+	require.Equal(t, root, client.History().Root())
+	again, err := client.History().Prove(1)
+	require.NoError(t, err)
+	require.Equal(t, proof, again)
+
+	// Stored network
+	network, err := LoadN3Network(st)
+	require.NoError(t, err)
+	require.Equal(t, uint32(860833102), network)
+	_, err = ResumeN3(st, nil, 894710606)
+	require.ErrorIs(t, err, ErrNetworkMismatch)
+
+	// Wrong network
+	st = store.NewMemory()
+	client, err = ResumeN3(st, trusted, 894710606)
+	require.NoError(t, err)
+	err = New[*block.Header](&N3Chain{Client: client, Source: n3.NewRPCSource(server.URL)}, st, Config{}).Run(context.Background())
+	require.ErrorIs(t, err, n3.ErrSignature)
+}
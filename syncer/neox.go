@@ -0,0 +1,132 @@
+package syncer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/txhsl/dbft-verifier/store"
+	neox "github.com/txhsl/neox-dbft-verifier"
+)
+
+// NeoXChain is Chain of Neo X, headers are stored in RLP encoding.
+type NeoXChain struct {
+	Client *neox.LightClient
+	Source neox.HeaderSource
+}
+
+// ResumeNeoX returns a light client following the head of st, or trusted if st is
+// empty, that is stored then. The history of the client is rebuilt from the stored
+// headers, so its leaf indexes and roots are the ones of the client that stored them.
+func ResumeNeoX(st *store.Store, trusted *types.Header, config *neox.ChainConfig) (*neox.LightClient, error) {
+	_, data, err := st.Head()
+	if errors.Is(err, store.ErrNotFound) {
+		if trusted == nil {
+			return nil, errors.New("empty store and no trusted header")
+		}
+		header, err := encodeNeoXHeader(trusted)
+		if err != nil {
+			return nil, err
+		}
+		if err := st.Put(trusted.Number.Uint64(), []store.Header{header}); err != nil {
+			return nil, err
+		}
+		return neox.NewLightClient(config, trusted), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	head, err := DecodeNeoXHeader(data)
+	if err != nil {
+		return nil, err
+	}
+	history := neox.NewMMR()
+	err = replay(st, func(data []byte) error {
+		header, err := DecodeNeoXHeader(data)
+		if err != nil {
+			return err
+		}
+		history.Append(header.Hash())
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return neox.ResumeLightClient(config, head, history), nil
+}
+
+// ErrConfigMismatch is returned when a store was synced with another chain config.
+var ErrConfigMismatch = errors.New("store was synced with another chain config")
+
+// LoadNeoXConfig returns the chain config st was synced with, or store.ErrNotFound if
+// none is stored.
+func LoadNeoXConfig(st *store.Store) (*neox.ChainConfig, error) {
+	data, err := st.Config()
+	if err != nil {
+		return nil, err
+	}
+	config := new(neox.ChainConfig)
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// SaveNeoXConfig stores config as the chain config st is synced with, it returns
+// ErrConfigMismatch if another one is stored.
+func SaveNeoXConfig(st *store.Store, config *neox.ChainConfig) error {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+	stored, err := st.Config()
+	switch {
+	case errors.Is(err, store.ErrNotFound):
+		return st.PutConfig(data)
+	case err != nil:
+		return err
+	case !bytes.Equal(stored, data):
+		return ErrConfigMismatch
+	}
+	return nil
+}
+
+// DecodeNeoXHeader decodes a stored Neo X header.
+func DecodeNeoXHeader(data []byte) (*types.Header, error) {
+	header := new(types.Header)
+	if err := rlp.DecodeBytes(data, header); err != nil {
+		return nil, err
+	}
+	return header, nil
+}
+
+func encodeNeoXHeader(h *types.Header) (store.Header, error) {
+	data, err := rlp.EncodeToBytes(h)
+	if err != nil {
+		return store.Header{}, err
+	}
+	return store.Header{Hash: h.Hash().Bytes(), Data: data}, nil
+}
+
+func (c *NeoXChain) Head() uint64 {
+	return c.Client.Head().Number.Uint64()
+}
+
+func (c *NeoXChain) Tip(ctx context.Context) (uint64, error) {
+	return c.Source.Height(ctx)
+}
+
+func (c *NeoXChain) Fetch(ctx context.Context, from uint64, count int) ([]*types.Header, error) {
+	return c.Source.Headers(ctx, from, count)
+}
+
+func (c *NeoXChain) Update(h *types.Header, commit func() error) error {
+	return c.Client.UpdateWith(h, commit)
+}
+
+func (c *NeoXChain) Encode(h *types.Header) (store.Header, error) {
+	return encodeNeoXHeader(h)
+}
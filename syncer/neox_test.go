@@ -0,0 +1,83 @@
+package syncer
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+	"github.com/txhsl/dbft-verifier/store"
+	neox "github.com/txhsl/neox-dbft-verifier"
+	neoxrpctest "github.com/txhsl/neox-dbft-verifier/rpctest"
+)
+
+func TestNeoXChain(t *testing.T) {
+	data, err := os.ReadFile("../bundle/testdata/neox_headers.json")
+	require.NoError(t, err)
+	var raws []json.RawMessage
+	require.NoError(t, json.Unmarshal(data, &raws))
+	server, err := neoxrpctest.NewServer(raws)
+	require.NoError(t, err)
+	defer server.Close()
+	trusted := new(types.Header)
+	require.NoError(t, trusted.UnmarshalJSON(raws[0]))
+	source, err := neox.DialRPCSource(context.Background(), server.URL)
+	require.NoError(t, err)
+	defer source.Close()
+
+	st := store.NewMemory()
+	client, err := ResumeNeoX(st, trusted, neox.DefaultChainConfig)
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- New[*types.Header](&NeoXChain{Client: client, Source: source}, st, Config{PollInterval: time.Millisecond}).Run(ctx)
+	}()
+	waitHead(t, st, 0x1fdc40)
+	cancel()
+	require.ErrorIs(t, <-done, context.Canceled)
+	root := client.History().Root()
+	proof, err := client.History().Prove(1)
+	require.NoError(t, err)
+
+	// Resume with the history
+	client, err = ResumeNeoX(st, nil, neox.DefaultChainConfig)
+	require.NoError(t, err)
+	require.Equal(t, uint64(0x1fdc40), client.Head().Number.Uint64())
+	stored, err := st.Get(0x1fdc3e)
+	require.NoError(t, err)
+	header, err := DecodeNeoXHeader(stored)
+	require.NoError(t, err)
+	require.Equal(t, trusted.Hash(), header.Hash())
+	require.Equal(t, root, client.History().Root())
+	again, err := client.History().Prove(1)
+	require.NoError(t, err)
+	require.Equal(t, proof, again)
+
+	// Threshold scheme is not scheduled
+	st = store.NewMemory()
+	config := &neox.ChainConfig{ShanghaiTime: neox.DefaultChainConfig.ShanghaiTime, Extra: neox.ExtraSchedule{{Block: 0, Version: neox.ExtraV1, Schemes: []byte{neox.ExtraV1ECDSAScheme}}}}
+	client, err = ResumeNeoX(st, trusted, config)
+	require.NoError(t, err)
+	err = New[*types.Header](&NeoXChain{Client: client, Source: source}, st, Config{}).Run(context.Background())
+	require.ErrorIs(t, err, neox.ErrExtra)
+}
+
+func TestNeoXConfig(t *testing.T) {
+	st := store.NewMemory()
+	_, err := LoadNeoXConfig(st)
+	require.ErrorIs(t, err, store.ErrNotFound)
+	scheduled := &neox.ChainConfig{Extra: neox.ExtraSchedule{
+		{Block: 0, Version: neox.ExtraV0},
+		{Block: 100, Version: neox.ExtraV1, Schemes: []byte{neox.ExtraV1ThresholdScheme}, AllowECDSAFallback: true},
+	}}
+	require.NoError(t, SaveNeoXConfig(st, scheduled))
+	config, err := LoadNeoXConfig(st)
+	require.NoError(t, err)
+	require.Equal(t, scheduled, config)
+	require.NoError(t, SaveNeoXConfig(st, config))
+	require.ErrorIs(t, SaveNeoXConfig(st, &neox.ChainConfig{}), ErrConfigMismatch)
+}
@@ -0,0 +1,217 @@
+// Package syncer follows a chain with a light client, persisting verified headers.
+package syncer
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/txhsl/dbft-verifier/store"
+)
+
+// Chain adapts a light client and a header source of a chain to Syncer.
+type Chain[H any] interface {
+	// Head returns the height of the light client head.
+	Head() uint64
+	// Tip returns the height of the latest header available from the source.
+	Tip(ctx context.Context) (uint64, error)
+	// Fetch returns count consecutive headers starting at height from.
+	Fetch(ctx context.Context, from uint64, count int) ([]H, error)
+	// Update verifies h against the head, calls commit and makes h the new head
+	// unless commit fails.
+	Update(h H, commit func() error) error
+	// Encode returns the stored encoding and the hash of h.
+	Encode(h H) (store.Header, error)
+}
+
+// Progress is reported periodically while syncing.
+type Progress struct {
+	Height uint64
+	Tip    uint64
+	// Rate is the number of verified headers per second since the previous report.
+	Rate float64
+}
+
+// Lag returns the number of headers behind the tip.
+func (p Progress) Lag() uint64 {
+	if p.Tip <= p.Height {
+		return 0
+	}
+	return p.Tip - p.Height
+}
+
+// Config is the Syncer settings, zero values are replaced with defaults.
+type Config struct {
+	BatchSize      int           // Headers per fetch, 100 by default.
+	Pipeline       int           // Fetched batches queued for verification, 4 by default.
+	PollInterval   time.Duration // Tip polling interval once synced, 1s by default.
+	ReportInterval time.Duration // Progress reporting interval, 10s by default.
+
+	// Report is called with the progress, OnFetchError with errors of the source, that
+	// are retried after PollInterval, and OnStored with the new head height once a
+	// batch is stored. All are optional.
+	Report       func(Progress)
+	OnFetchError func(error)
+	OnStored     func(height uint64)
+}
+
+// Syncer pulls headers from a source, verifies and stores them. Fetching runs ahead of
+// verification, so both proceed concurrently.
+type Syncer[H any] struct {
+	chain Chain[H]
+	store *store.Store
+	cfg   Config
+}
+
+type batch[H any] struct {
+	from    uint64
+	tip     uint64
+	headers []H
+}
+
+// New returns Syncer of chain storing headers to st.
+func New[H any](chain Chain[H], st *store.Store, cfg Config) *Syncer[H] {
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 100
+	}
+	if cfg.Pipeline <= 0 {
+		cfg.Pipeline = 4
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = time.Second
+	}
+	if cfg.ReportInterval <= 0 {
+		cfg.ReportInterval = 10 * time.Second
+	}
+	return &Syncer[H]{chain: chain, store: st, cfg: cfg}
+}
+
+// Run syncs until ctx is done or a header fails verification, it returns ctx error in
+// the former case.
+func (s *Syncer[H]) Run(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	batches := make(chan batch[H], s.cfg.Pipeline)
+	go s.fetch(ctx, s.chain.Head()+1, batches)
+
+	ticker := time.NewTicker(s.cfg.ReportInterval)
+	defer ticker.Stop()
+	var (
+		progress = Progress{Height: s.chain.Head()}
+		verified int
+		last     = time.Now()
+	)
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case now := <-ticker.C:
+			progress.Rate = float64(verified) / now.Sub(last).Seconds()
+			verified, last = 0, now
+			if s.cfg.Report != nil {
+				s.cfg.Report(progress)
+			}
+		case b := <-batches:
+			// Headers are stored before they become the head, so the store never lags
+			// behind the client
+			for i, h := range b.headers {
+				height := b.from + uint64(i)
+				data, err := s.chain.Encode(h)
+				if err != nil {
+					return fmt.Errorf("header %d: %w", height, err)
+				}
+				err = s.chain.Update(h, func() error {
+					return s.store.Put(height, []store.Header{data})
+				})
+				if err != nil {
+					return fmt.Errorf("header %d: %w", height, err)
+				}
+			}
+			verified += len(b.headers)
+			progress.Height = b.from + uint64(len(b.headers)) - 1
+			progress.Tip = max(progress.Tip, b.tip)
+			if len(b.headers) > 0 && s.cfg.OnStored != nil {
+				s.cfg.OnStored(progress.Height)
+			}
+		}
+	}
+}
+
+func (s *Syncer[H]) fetch(ctx context.Context, next uint64, batches chan<- batch[H]) {
+	wait := func() bool {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(s.cfg.PollInterval):
+			return true
+		}
+	}
+	var tip uint64
+	for {
+		if next > tip {
+			t, err := s.chain.Tip(ctx)
+			if err != nil {
+				s.fetchError(ctx, err)
+				if !wait() {
+					return
+				}
+				continue
+			}
+			tip = t
+			if next > tip {
+				// Synced, report the tip anyway
+				select {
+				case batches <- batch[H]{from: next, tip: tip}:
+				default:
+				}
+				if !wait() {
+					return
+				}
+				continue
+			}
+		}
+		count := int(min(uint64(s.cfg.BatchSize), tip-next+1))
+		headers, err := s.chain.Fetch(ctx, next, count)
+		if err != nil {
+			s.fetchError(ctx, err)
+			if !wait() {
+				return
+			}
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case batches <- batch[H]{from: next, tip: tip, headers: headers}:
+		}
+		next += uint64(len(headers))
+	}
+}
+
+func (s *Syncer[H]) fetchError(ctx context.Context, err error) {
+	if ctx.Err() == nil && s.cfg.OnFetchError != nil {
+		s.cfg.OnFetchError(err)
+	}
+}
+
+// replay calls f with stored headers from the lowest to the head.
+func replay(st *store.Store, f func(data []byte) error) error {
+	tail, err := st.Tail()
+	if err != nil {
+		return err
+	}
+	head, _, err := st.Head()
+	if err != nil {
+		return err
+	}
+	for height := tail; height <= head; height++ {
+		data, err := st.Get(height)
+		if err != nil {
+			return fmt.Errorf("header %d: %w", height, err)
+		}
+		if err := f(data); err != nil {
+			return fmt.Errorf("header %d: %w", height, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package syncer
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/txhsl/dbft-verifier/store"
+)
+
+// testChain has header h at height h, bad is rejected.
+type testChain struct {
+	head     atomic.Uint64
+	tip      atomic.Uint64
+	bad      uint64
+	failTips atomic.Int32
+}
+
+func (c *testChain) Head() uint64 { return c.head.Load() }
+
+func (c *testChain) Tip(ctx context.Context) (uint64, error) {
+	if c.failTips.Add(-1) >= 0 {
+		return 0, errors.New("unavailable")
+	}
+	return c.tip.Load(), nil
+}
+
+func (c *testChain) Fetch(ctx context.Context, from uint64, count int) ([]uint64, error) {
+	headers := make([]uint64, count)
+	for i := range headers {
+		headers[i] = from + uint64(i)
+	}
+	return headers, nil
+}
+
+func (c *testChain) Update(h uint64, commit func() error) error {
+	if h != c.head.Load()+1 || h == c.bad {
+		return errors.New("rejected")
+	}
+	if err := commit(); err != nil {
+		return err
+	}
+	c.head.Store(h)
+	return nil
+}
+
+func (c *testChain) Encode(h uint64) (store.Header, error) {
+	return store.Header{Hash: []byte{0xff, byte(h)}, Data: []byte{byte(h)}}, nil
+}
+
+func waitHead(t *testing.T, st *store.Store, height uint64) {
+	require.Eventually(t, func() bool {
+		head, _, err := st.Head()
+		return err == nil && head == height
+	}, 5*time.Second, time.Millisecond)
+}
+
+func TestSyncer(t *testing.T) {
+	st := store.NewMemory()
+	require.NoError(t, st.Put(0, []store.Header{{Hash: []byte{0xff, 0}, Data: []byte{0}}}))
+	chain := new(testChain)
+	chain.tip.Store(250)
+	chain.failTips.Store(2)
+
+	var (
+		lock      sync.Mutex
+		reports   []Progress
+		fetchErrs atomic.Int32
+		stored    atomic.Uint64
+	)
+	s := New[uint64](chain, st, Config{
+		BatchSize:      16,
+		PollInterval:   time.Millisecond,
+		ReportInterval: time.Millisecond,
+		Report: func(p Progress) {
+			lock.Lock()
+			reports = append(reports, p)
+			lock.Unlock()
+		},
+		OnFetchError: func(error) { fetchErrs.Add(1) },
+		OnStored:     func(height uint64) { stored.Store(height) },
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- s.Run(ctx) }()
+	waitHead(t, st, 250)
+
+	// Follow the tip
+	chain.tip.Store(300)
+	waitHead(t, st, 300)
+	require.Eventually(t, func() bool {
+		lock.Lock()
+		defer lock.Unlock()
+		last := reports[len(reports)-1]
+		return last.Height == 300 && last.Tip == 300 && last.Lag() == 0
+	}, 5*time.Second, time.Millisecond)
+	cancel()
+	require.ErrorIs(t, <-done, context.Canceled)
+	require.Equal(t, int32(2), fetchErrs.Load())
+	require.Equal(t, uint64(300), stored.Load())
+	header, err := st.Get(200)
+	require.NoError(t, err)
+	require.Equal(t, []byte{200}, header)
+
+	// Resume from the stored head and stop on a bad header
+	head, _, err := st.Head()
+	require.NoError(t, err)
+	chain = &testChain{bad: 350}
+	chain.head.Store(head)
+	chain.tip.Store(400)
+	err = New[uint64](chain, st, Config{PollInterval: time.Millisecond}).Run(context.Background())
+	require.ErrorContains(t, err, "header 350")
+	head, _, err = st.Head()
+	require.NoError(t, err)
+	require.Equal(t, uint64(349), head)
+	require.Equal(t, uint64(349), chain.Head())
+
+	// The head doesn't advance past a failed write
+	chain.bad = 0
+	require.NoError(t, st.Close())
+	err = New[uint64](chain, st, Config{PollInterval: time.Millisecond}).Run(context.Background())
+	require.ErrorContains(t, err, "header 350")
+	require.Equal(t, uint64(349), chain.Head())
+}